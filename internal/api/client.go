@@ -0,0 +1,158 @@
+// Package api is a typed client for the vibe-kanban HTTP and websocket API.
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client talks to a single vibe-kanban server.
+type Client struct {
+	// BaseURL is the HTTP API root, e.g. "http://localhost:8096/api".
+	BaseURL string
+	// WSBaseURL is the websocket API root, e.g. "ws://localhost:8096/api".
+	WSBaseURL string
+
+	HTTPClient *http.Client
+	Dialer     *websocket.Dialer
+}
+
+// New returns a client for the given HTTP and websocket API roots.
+func New(baseURL, wsBaseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		WSBaseURL:  strings.TrimRight(wsBaseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		Dialer:     websocket.DefaultDialer,
+	}
+}
+
+// ErrUnreachable wraps transport failures such as a refused connection, so
+// that callers can tell an unreachable server apart from API errors.
+var ErrUnreachable = errors.New("server unreachable")
+
+// Error is returned when the server answers with a non-2xx status or an
+// envelope whose success flag is false.
+type Error struct {
+	StatusCode int
+	Message    string
+	ErrorData  json.RawMessage
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && len(e.ErrorData) > 0 && string(e.ErrorData) != "null" {
+		msg = string(e.ErrorData)
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("server error (status %d): %s", e.StatusCode, msg)
+	}
+	return fmt.Sprintf("server error: %s", msg)
+}
+
+// envelope is the wrapper every vibe-kanban endpoint responds with.
+type envelope struct {
+	Success   bool            `json:"success"`
+	Data      json.RawMessage `json:"data"`
+	ErrorData json.RawMessage `json:"error_data"`
+	Message   *string         `json:"message"`
+}
+
+// Get fetches path (relative to BaseURL) and decodes the envelope's data
+// into out. out may be nil when the caller does not need the payload.
+func (c *Client) Get(path string, query url.Values, out interface{}) error {
+	return c.do(http.MethodGet, path, query, nil, out)
+}
+
+// Post sends body as JSON and decodes the envelope's data into out.
+func (c *Client) Post(path string, body, out interface{}) error {
+	return c.do(http.MethodPost, path, nil, body, out)
+}
+
+// Put sends body as JSON and decodes the envelope's data into out.
+func (c *Client) Put(path string, body, out interface{}) error {
+	return c.do(http.MethodPut, path, nil, body, out)
+}
+
+// Delete removes the resource at path.
+func (c *Client) Delete(path string) error {
+	return c.do(http.MethodDelete, path, nil, nil, nil)
+}
+
+func (c *Client) do(method, path string, query url.Values, body, out interface{}) error {
+	endpoint := c.BaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return decodeEnvelope(resp.StatusCode, raw, out)
+}
+
+func decodeEnvelope(statusCode int, raw []byte, out interface{}) error {
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		if statusCode >= 400 {
+			return &Error{StatusCode: statusCode, Message: strings.TrimSpace(string(raw))}
+		}
+		return fmt.Errorf("unexpected response (status %d): %w", statusCode, err)
+	}
+
+	if statusCode >= 400 || !env.Success {
+		apiErr := &Error{StatusCode: statusCode, ErrorData: env.ErrorData}
+		if env.Message != nil {
+			apiErr.Message = *env.Message
+		}
+		return apiErr
+	}
+
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decode response data: %w", err)
+	}
+	return nil
+}
+
+func pathID(id string) string {
+	return url.PathEscape(strings.TrimSpace(id))
+}
@@ -0,0 +1,179 @@
+package api
+
+import "net/url"
+
+// ListProjects returns every project.
+func (c *Client) ListProjects() ([]Project, error) {
+	var projects []Project
+	if err := c.Get("/projects", nil, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProject returns a single project.
+func (c *Client) GetProject(projectID string) (*Project, error) {
+	var project Project
+	if err := c.Get("/projects/"+pathID(projectID), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListBranches returns the branches of a project's repository.
+func (c *Client) ListBranches(projectID string) ([]GitBranch, error) {
+	var branches []GitBranch
+	if err := c.Get("/projects/"+pathID(projectID)+"/branches", nil, &branches); err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// GetSystemInfo returns the server's system information, including the
+// executor profiles.
+func (c *Client) GetSystemInfo() (*SystemInfo, error) {
+	var info SystemInfo
+	if err := c.Get("/info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ListTasks returns the tasks of a project.
+func (c *Client) ListTasks(projectID string) ([]Task, error) {
+	var tasks []Task
+	query := url.Values{"project_id": {projectID}}
+	if err := c.Get("/tasks", query, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTask returns a single task.
+func (c *Client) GetTask(taskID string) (*Task, error) {
+	var task Task
+	if err := c.Get("/tasks/"+pathID(taskID), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListAttempts returns the attempts of a task, oldest first.
+func (c *Client) ListAttempts(taskID string) ([]TaskAttempt, error) {
+	var attempts []TaskAttempt
+	query := url.Values{"task_id": {taskID}}
+	if err := c.Get("/task-attempts", query, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// GetAttempt returns a single task attempt.
+func (c *Client) GetAttempt(attemptID string) (*TaskAttempt, error) {
+	var attempt TaskAttempt
+	if err := c.Get("/task-attempts/"+pathID(attemptID), nil, &attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// CreateAttempt starts a new attempt for a task.
+func (c *Client) CreateAttempt(req CreateAttemptRequest) (*TaskAttempt, error) {
+	var attempt TaskAttempt
+	if err := c.Post("/task-attempts", req, &attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// GetBranchStatus returns how an attempt's branch compares with its base
+// branch.
+func (c *Client) GetBranchStatus(attemptID string) (*BranchStatus, error) {
+	var status BranchStatus
+	if err := c.Get("/task-attempts/"+pathID(attemptID)+"/branch-status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// MergeAttempt merges an attempt's branch into its base branch.
+func (c *Client) MergeAttempt(attemptID string) error {
+	return c.Post("/task-attempts/"+pathID(attemptID)+"/merge", nil, nil)
+}
+
+// RebaseAttempt rebases an attempt's branch. A conflict is reported as an
+// *Error whose ErrorData decodes into GitOperationError.
+func (c *Client) RebaseAttempt(attemptID string, req RebaseRequest) error {
+	return c.Post("/task-attempts/"+pathID(attemptID)+"/rebase", req, nil)
+}
+
+// PushAttempt pushes an attempt's branch to the remote.
+func (c *Client) PushAttempt(attemptID string) error {
+	return c.Post("/task-attempts/"+pathID(attemptID)+"/push", nil, nil)
+}
+
+// CreatePR opens a pull request for an attempt's branch and returns its URL.
+func (c *Client) CreatePR(attemptID string, req CreatePRRequest) (string, error) {
+	var prURL string
+	if err := c.Post("/task-attempts/"+pathID(attemptID)+"/pr", req, &prURL); err != nil {
+		return "", err
+	}
+	return prURL, nil
+}
+
+// ListExecutionProcesses returns the execution processes of an attempt.
+func (c *Client) ListExecutionProcesses(attemptID string) ([]ExecutionProcess, error) {
+	var processes []ExecutionProcess
+	query := url.Values{"task_attempt_id": {attemptID}}
+	if err := c.Get("/execution-processes", query, &processes); err != nil {
+		return nil, err
+	}
+	return processes, nil
+}
+
+// CreateTask adds a task to a project.
+func (c *Client) CreateTask(req CreateTaskRequest) (*Task, error) {
+	var task Task
+	if err := c.Post("/tasks", req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask replaces the title, description and status of a task.
+func (c *Client) UpdateTask(taskID string, req UpdateTaskRequest) (*Task, error) {
+	var task Task
+	if err := c.Put("/tasks/"+pathID(taskID), req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask removes a task together with its attempts.
+func (c *Client) DeleteTask(taskID string) error {
+	return c.Delete("/tasks/" + pathID(taskID))
+}
+
+// GetExecutionProcess returns a single execution process.
+func (c *Client) GetExecutionProcess(processID string) (*ExecutionProcess, error) {
+	var process ExecutionProcess
+	if err := c.Get("/execution-processes/"+pathID(processID), nil, &process); err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+// StopExecutionProcess kills a running execution process.
+func (c *Client) StopExecutionProcess(processID string) error {
+	return c.Post("/execution-processes/"+pathID(processID)+"/stop", nil, nil)
+}
+
+// FollowUp sends a follow-up prompt to an attempt and returns the execution
+// process it started.
+func (c *Client) FollowUp(attemptID string, req FollowUpRequest) (*ExecutionProcess, error) {
+	var process ExecutionProcess
+	if err := c.Post("/task-attempts/"+pathID(attemptID)+"/follow-up", req, &process); err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gorilla/websocket"
+
+	"vkcli/internal/jsonpatch"
+)
+
+// LogMessage is one message of a patch stream such as the normalized-logs
+// websocket. Exactly one of JSONPatch, Ready or Finished is set.
+type LogMessage struct {
+	JSONPatch []jsonpatch.Operation `json:"JsonPatch,omitempty"`
+	// Ready marks the end of the initial snapshot of streams that stay open
+	// for live updates, such as the diff of an attempt.
+	Ready    bool `json:"Ready,omitempty"`
+	Finished bool `json:"finished,omitempty"`
+
+	// Raw holds the undecoded message for debugging.
+	Raw json.RawMessage `json:"-"`
+}
+
+// StreamNormalizedLogs connects to the normalized-logs websocket of an
+// execution process and calls fn for every message until the server sends
+// the finished marker, closes the connection, fn returns an error or ctx is
+// cancelled.
+func (c *Client) StreamNormalizedLogs(ctx context.Context, processID string, fn func(LogMessage) error) error {
+	return c.streamPatches(ctx, fmt.Sprintf("/execution-processes/%s/normalized-logs/ws", pathID(processID)), fn)
+}
+
+// StreamAttemptDiff connects to the diff websocket of an attempt. The server
+// sends one entry per changed file under /entries, keyed by path, then keeps
+// the stream open for live changes; fn sees a Ready message once the initial
+// snapshot is complete. It returns under the same conditions as
+// StreamNormalizedLogs.
+func (c *Client) StreamAttemptDiff(ctx context.Context, attemptID string, fn func(LogMessage) error) error {
+	return c.streamPatches(ctx, fmt.Sprintf("/task-attempts/%s/diff/ws", pathID(attemptID)), fn)
+}
+
+func (c *Client) streamPatches(ctx context.Context, path string, fn func(LogMessage) error) error {
+	endpoint := c.WSBaseURL + path
+	conn, resp, err := c.Dialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		if resp == nil && ctx.Err() == nil {
+			return fmt.Errorf("error connecting WS: %w: %w", ErrUnreachable, err)
+		}
+		return fmt.Errorf("error connecting WS: %w", err)
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return fmt.Errorf("reading stream: %w", err)
+		}
+
+		var msg LogMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			continue
+		}
+		msg.Raw = raw
+		if err := fn(msg); err != nil {
+			return err
+		}
+		if msg.Finished {
+			return nil
+		}
+	}
+}
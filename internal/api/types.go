@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Project is a vibe-kanban project.
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	GitRepoPath string    `json:"git_repo_path"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Task is a task as returned by the task list and task detail endpoints.
+type Task struct {
+	ID                   string    `json:"id"`
+	ProjectID            string    `json:"project_id"`
+	Title                string    `json:"title"`
+	Description          string    `json:"description"`
+	Status               string    `json:"status"`
+	ParentTaskAttempt    string    `json:"parent_task_attempt,omitempty"`
+	HasInProgressAttempt bool      `json:"has_in_progress_attempt"`
+	HasMergedAttempt     bool      `json:"has_merged_attempt"`
+	LastAttemptFailed    bool      `json:"last_attempt_failed"`
+	Executor             string    `json:"executor,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// TaskAttempt is a single run of a coding agent against a task.
+type TaskAttempt struct {
+	ID              string    `json:"id"`
+	TaskID          string    `json:"task_id"`
+	ContainerRef    string    `json:"container_ref,omitempty"`
+	Branch          string    `json:"branch,omitempty"`
+	BaseBranch      string    `json:"base_branch"`
+	Executor        string    `json:"executor"`
+	WorktreeDeleted bool      `json:"worktree_deleted"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ExecutorProfileID selects an executor and an optional variant.
+type ExecutorProfileID struct {
+	Executor string `json:"executor"`
+	Variant  string `json:"variant,omitempty"`
+}
+
+// CreateAttemptRequest is the body of POST /task-attempts.
+type CreateAttemptRequest struct {
+	TaskID            string            `json:"task_id"`
+	BaseBranch        string            `json:"base_branch"`
+	ExecutorProfileID ExecutorProfileID `json:"executor_profile_id"`
+}
+
+// ExecutorAction describes what an execution process runs.
+type ExecutorAction struct {
+	Typ struct {
+		Type              string             `json:"type"`
+		Prompt            string             `json:"prompt"`
+		ExecutorProfileID *ExecutorProfileID `json:"executor_profile_id,omitempty"`
+	} `json:"typ"`
+}
+
+// ExecutionProcess is a setup script, coding agent or cleanup script run
+// belonging to a task attempt.
+type ExecutionProcess struct {
+	ID             string         `json:"id"`
+	TaskAttemptID  string         `json:"task_attempt_id"`
+	RunReason      string         `json:"run_reason"`
+	ExecutorAction ExecutorAction `json:"executor_action"`
+	Status         string         `json:"status"`
+	ExitCode       *int64         `json:"exit_code,omitempty"`
+	StartedAt      time.Time      `json:"started_at"`
+	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// CreateTaskRequest is the body of POST /tasks.
+type CreateTaskRequest struct {
+	ProjectID   string `json:"project_id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateTaskRequest is the body of PUT /tasks/{id}.
+type UpdateTaskRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+// FollowUpRequest is the body of POST /task-attempts/{id}/follow-up.
+type FollowUpRequest struct {
+	Prompt  string `json:"prompt"`
+	Variant string `json:"variant,omitempty"`
+}
+
+// GitBranch is a branch of a project's repository.
+type GitBranch struct {
+	Name      string `json:"name"`
+	IsCurrent bool   `json:"is_current"`
+	IsRemote  bool   `json:"is_remote"`
+}
+
+// BranchStatus describes an attempt's branch relative to its base branch.
+type BranchStatus struct {
+	CommitsAhead          int      `json:"commits_ahead"`
+	CommitsBehind         int      `json:"commits_behind"`
+	HasUncommittedChanges bool     `json:"has_uncommitted_changes"`
+	BaseBranchName        string   `json:"base_branch_name"`
+	IsRebaseInProgress    bool     `json:"is_rebase_in_progress"`
+	ConflictedFiles       []string `json:"conflicted_files"`
+	// ConflictOp names the git operation that left conflicts, e.g. "rebase".
+	ConflictOp string `json:"conflict_op,omitempty"`
+}
+
+// RebaseRequest is the body of POST /task-attempts/:id/rebase. An empty
+// NewBaseBranch rebases onto the attempt's current base branch.
+type RebaseRequest struct {
+	NewBaseBranch string `json:"new_base_branch,omitempty"`
+}
+
+// CreatePRRequest is the body of POST /task-attempts/:id/pr.
+type CreatePRRequest struct {
+	Title      string `json:"title"`
+	Body       string `json:"body,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	Draft      bool   `json:"draft,omitempty"`
+}
+
+// GitOperationError is the error_data of a failed merge or rebase.
+type GitOperationError struct {
+	Type            string   `json:"type"`
+	Message         string   `json:"message,omitempty"`
+	Op              string   `json:"op,omitempty"`
+	ConflictedFiles []string `json:"conflicted_files,omitempty"`
+}
+
+// FileDiff is one changed file of an attempt's diff stream. The server sends
+// the whole old and new contents; ContentOmitted is set when a file is too
+// large or binary and they were left out.
+type FileDiff struct {
+	Change         string `json:"change"`
+	OldPath        string `json:"oldPath,omitempty"`
+	NewPath        string `json:"newPath,omitempty"`
+	OldContent     string `json:"oldContent,omitempty"`
+	NewContent     string `json:"newContent,omitempty"`
+	ContentOmitted bool   `json:"contentOmitted,omitempty"`
+	Additions      int    `json:"additions,omitempty"`
+	Deletions      int    `json:"deletions,omitempty"`
+}
+
+// SystemInfo is the part of GET /info that vkcli uses.
+type SystemInfo struct {
+	// Executors maps each executor to its profile variants.
+	Executors map[string]map[string]json.RawMessage `json:"executors"`
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/output"
+)
+
+const attemptsUsage = "vkcli attempts <task_id> [--output <fmt>]"
+
+type AttemptsCommand struct{}
+
+func NewAttemptsCommand() Command {
+	return &AttemptsCommand{}
+}
+
+func (c *AttemptsCommand) Name() string {
+	return "attempts"
+}
+
+func (c *AttemptsCommand) Usage() string {
+	return attemptsUsage
+}
+
+func (c *AttemptsCommand) Description() string {
+	return "タスクの attempt 一覧"
+}
+
+// attemptSummary is an attempt together with what its execution processes
+// tell about it.
+type attemptSummary struct {
+	ID              string    `json:"id"`
+	TaskID          string    `json:"task_id"`
+	Executor        string    `json:"executor"`
+	Branch          string    `json:"branch"`
+	BaseBranch      string    `json:"base_branch"`
+	CreatedAt       time.Time `json:"created_at"`
+	Duration        string    `json:"duration"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Status          string    `json:"status"`
+	Processes       int       `json:"processes"`
+}
+
+func (c *AttemptsCommand) Run(args []string) error {
+	args, format, err := parseOutputFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: %s", attemptsUsage)
+	}
+
+	client := apiClient()
+	attempts, err := client.ListAttempts(args[0])
+	if err != nil {
+		return err
+	}
+
+	summaries := make([]attemptSummary, 0, len(attempts))
+	for _, a := range attempts {
+		summary, err := summarizeAttempt(client, a)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if !format.IsTable() {
+		return writeOutput(format, summaries, attemptColumns(summaries))
+	}
+	if len(summaries) == 0 {
+		fmt.Println("No attempts found.")
+		return nil
+	}
+
+	fmt.Printf("%-38s  %-12s  %-24s  %-10s  %-19s  %8s  %-9s  %5s\n",
+		"ATTEMPT ID", "EXECUTOR", "BRANCH", "BASE", "CREATED", "DURATION", "STATUS", "PROCS")
+	fmt.Println(strings.Repeat("-", 142))
+	for _, s := range summaries {
+		fmt.Printf("%-38s  %-12s  %-24s  %-10s  %-19s  %8s  %-9s  %5d\n",
+			s.ID, s.Executor, truncateText(s.Branch, 24), s.BaseBranch, formatTime(s.CreatedAt),
+			s.Duration, s.Status, s.Processes)
+	}
+	return nil
+}
+
+// summarizeAttempt derives the duration, final status and process count of
+// an attempt from its execution processes. The duration runs from the first
+// process start to the last completion, or to now while one is running.
+func summarizeAttempt(client *api.Client, a api.TaskAttempt) (attemptSummary, error) {
+	s := attemptSummary{
+		ID:         a.ID,
+		TaskID:     a.TaskID,
+		Executor:   a.Executor,
+		Branch:     a.Branch,
+		BaseBranch: a.BaseBranch,
+		CreatedAt:  a.CreatedAt,
+		Duration:   "-",
+		Status:     "-",
+	}
+
+	processes, err := client.ListExecutionProcesses(a.ID)
+	if err != nil {
+		return s, err
+	}
+	s.Processes = len(processes)
+	if len(processes) == 0 {
+		return s, nil
+	}
+
+	var start, end time.Time
+	running := false
+	for _, p := range processes {
+		if start.IsZero() || (!p.StartedAt.IsZero() && p.StartedAt.Before(start)) {
+			start = p.StartedAt
+		}
+		if p.Status == "running" {
+			running = true
+		}
+		if p.CompletedAt != nil && p.CompletedAt.After(end) {
+			end = *p.CompletedAt
+		}
+	}
+	if running {
+		end = time.Now()
+	}
+	// Without a completion time the duration of a finished attempt is
+	// unknown; it stays "-".
+	if !start.IsZero() && !end.IsZero() {
+		d := end.Sub(start).Round(time.Second)
+		s.Duration = d.String()
+		s.DurationSeconds = d.Seconds()
+	}
+
+	s.Status = processes[len(processes)-1].Status
+	for i := len(processes) - 1; i >= 0; i-- {
+		if processes[i].RunReason == "codingagent" {
+			s.Status = processes[i].Status
+			break
+		}
+	}
+	if running {
+		s.Status = "running"
+	}
+	return s, nil
+}
+
+func attemptColumns(summaries []attemptSummary) output.Columns {
+	cols := output.Columns{Headers: []string{
+		"id", "executor", "branch", "base_branch", "created_at", "duration_seconds", "status", "processes",
+	}}
+	for _, s := range summaries {
+		cols.Rows = append(cols.Rows, []string{
+			s.ID, s.Executor, s.Branch, s.BaseBranch, formatRFC3339(s.CreatedAt),
+			strconv.FormatFloat(s.DurationSeconds, 'f', 0, 64), s.Status, strconv.Itoa(s.Processes),
+		})
+	}
+	return cols
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"vkcli/internal/api"
+)
+
+func TestSummarizeAttemptDuration(t *testing.T) {
+	// A quarter second short of an hour, so that the running attempt rounds
+	// to an hour however long the test takes up to 0.75s.
+	started := time.Now().Add(-time.Hour + 250*time.Millisecond)
+	completed := started.Add(90 * time.Second)
+	s := &prServer{
+		processes: map[string][]api.ExecutionProcess{
+			"done": {
+				{ID: "p1", RunReason: "codingagent", Status: "completed", StartedAt: started, CompletedAt: &completed},
+			},
+			"unfinished": {
+				{ID: "p2", RunReason: "codingagent", Status: "completed", StartedAt: started},
+			},
+			"running": {
+				{ID: "p3", RunReason: "codingagent", Status: "running", StartedAt: started},
+			},
+		},
+	}
+	s.start(t)
+
+	tests := []struct {
+		attemptID string
+		want      string
+	}{
+		{"done", "1m30s"},
+		{"unfinished", "-"},
+		{"running", "1h0m0s"},
+	}
+	for _, tt := range tests {
+		summary, err := summarizeAttempt(apiClient(), api.TaskAttempt{ID: tt.attemptID})
+		if err != nil {
+			t.Fatalf("summarizeAttempt(%s): %v", tt.attemptID, err)
+		}
+		if summary.Duration != tt.want {
+			t.Errorf("%s: got duration %s, want %s", tt.attemptID, summary.Duration, tt.want)
+		}
+	}
+}
@@ -1,3 +1,87 @@
 package commands
 
-const baseURL = "http://localhost:8096/api"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/config"
+)
+
+var cfg = config.Default()
+
+// SetConfig installs the resolved configuration used by every command.
+func SetConfig(c *config.Config) {
+	cfg = c
+}
+
+// baseURL returns the HTTP API root, e.g. "http://localhost:8096/api".
+func baseURL() string {
+	return cfg.APIBaseURL()
+}
+
+// wsBaseURL returns the websocket API root, e.g. "ws://localhost:8096/api".
+func wsBaseURL() string {
+	return cfg.WSBaseURL()
+}
+
+// apiClient returns a client for the configured server.
+func apiClient() *api.Client {
+	return api.New(baseURL(), wsBaseURL())
+}
+
+// formatTime renders a server timestamp in local time, or "-" when unset.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// formatRFC3339 renders a timestamp for machine-readable output.
+func formatRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// takeFlagValue matches args[*i] against "--name value" and "--name=value".
+// On a match it returns the value and advances *i past a separate value.
+func takeFlagValue(args []string, i *int, name string) (value string, ok bool, err error) {
+	arg := args[*i]
+	if strings.HasPrefix(arg, name+"=") {
+		return strings.TrimSpace(strings.TrimPrefix(arg, name+"=")), true, nil
+	}
+	if arg != name {
+		return "", false, nil
+	}
+	if *i+1 >= len(args) {
+		return "", true, fmt.Errorf("%s requires a value", name)
+	}
+	*i++
+	return strings.TrimSpace(args[*i]), true, nil
+}
+
+// parseSince accepts a duration ("90m", "24h", "7d"), a date ("2025-01-31")
+// or an RFC 3339 timestamp and returns the absolute point in time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected e.g. 24h, 7d, 2025-01-31 or RFC 3339)", value)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+
+	"vkcli/internal/config"
+)
+
+type ConfigCommand struct{}
+
+func NewConfigCommand() Command {
+	return &ConfigCommand{}
+}
+
+func (c *ConfigCommand) Name() string {
+	return "config"
+}
+
+func (c *ConfigCommand) Usage() string {
+	return "vkcli config show"
+}
+
+func (c *ConfigCommand) Description() string {
+	return "解決済みの設定を表示"
+}
+
+func (c *ConfigCommand) Run(args []string) error {
+	if len(args) != 1 || args[0] != "show" {
+		return fmt.Errorf("Usage: vkcli config show")
+	}
+
+	fileState := "not found"
+	if cfg.FileLoaded {
+		fileState = "loaded"
+	}
+	fmt.Printf("Config File:    %s (%s)\n", cfg.Path, fileState)
+	fmt.Printf("Server:         %s (%s)\n", cfg.Server, cfg.ServerSource)
+	fmt.Printf("API URL:        %s\n", baseURL())
+	fmt.Printf("WebSocket URL:  %s\n", wsBaseURL())
+	if cfg.Pager != "" {
+		fmt.Printf("Pager:          %s\n", cfg.Pager)
+	}
+	printExecDefaults("Defaults:", cfg.Defaults)
+	for _, id := range sortedKeys(cfg.Projects) {
+		printExecDefaults(fmt.Sprintf("Project %s:", id), cfg.Projects[id])
+	}
+	return nil
+}
+
+func printExecDefaults(label string, d config.ExecDefaults) {
+	if d == (config.ExecDefaults{}) {
+		return
+	}
+	fmt.Println(label)
+	for _, kv := range [][2]string{
+		{"executor", d.Executor},
+		{"variant", d.Variant},
+		{"base_branch", d.BaseBranch},
+	} {
+		if kv[1] != "" {
+			fmt.Printf("  %-12s  %s\n", kv[0], kv[1])
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/state"
+)
+
+const depsUsage = "vkcli deps <task_id> | vkcli deps add <task_id> <depends_on_id>... | " +
+	"vkcli deps remove <task_id> <depends_on_id>..."
+
+// dependsOnPattern matches "depends-on: <id>, <id>" lines in task
+// descriptions.
+var dependsOnPattern = regexp.MustCompile(`(?im)^[ \t]*depends-on:[ \t]*(.+?)[ \t]*$`)
+
+type DepsCommand struct{}
+
+func NewDepsCommand() Command {
+	return &DepsCommand{}
+}
+
+func (c *DepsCommand) Name() string {
+	return "deps"
+}
+
+func (c *DepsCommand) Usage() string {
+	return depsUsage
+}
+
+func (c *DepsCommand) Description() string {
+	return "タスクの依存関係を表示・設定"
+}
+
+func (c *DepsCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: %s", depsUsage)
+	}
+	switch args[0] {
+	case "add":
+		return runDepsChange(args[1:], true)
+	case "remove", "rm":
+		return runDepsChange(args[1:], false)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: %s", depsUsage)
+	}
+	return showDeps(args[0])
+}
+
+func showDeps(taskID string) error {
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		return err
+	}
+	client := apiClient()
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	fromDescription := descriptionDependencies(task.Description)
+	deps := taskDependencies(st, *task)
+	if len(deps) == 0 {
+		fmt.Printf("Task %s has no dependencies.\n", task.ID)
+		return nil
+	}
+
+	fmt.Printf("%-38s  %-40s  %-10s  %s\n", "DEPENDS ON", "TITLE", "STATUS", "SOURCE")
+	fmt.Println(strings.Repeat("-", 104))
+	for _, id := range deps {
+		title, status := "-", "UNKNOWN"
+		if dep, err := client.GetTask(id); err == nil {
+			title, status = dep.Title, normalizeStatusString(dep.Status)
+		}
+		source := "state"
+		if slices.Contains(fromDescription, id) {
+			source = "description"
+		}
+		fmt.Printf("%-38s  %-40s  %-10s  %s\n", id, truncateText(title, 40), status, source)
+	}
+	return nil
+}
+
+func runDepsChange(args []string, add bool) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: %s", depsUsage)
+	}
+	taskID, deps := args[0], args[1:]
+
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	if !add {
+		removed := st.RemoveDependencies(taskID, deps...)
+		for _, dep := range deps {
+			if !slices.Contains(removed, dep) {
+				fmt.Printf("%s is not recorded in the state file (depends-on lines must be edited in the description)\n", dep)
+			}
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+		if err := st.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed dependencies of %s: %s\n", taskID, strings.Join(removed, ", "))
+		return nil
+	}
+
+	client := apiClient()
+	for _, id := range append([]string{taskID}, deps...) {
+		if _, err := client.GetTask(id); err != nil {
+			return fmt.Errorf("task %s: %w", id, err)
+		}
+	}
+	if slices.Contains(deps, taskID) {
+		return fmt.Errorf("a task cannot depend on itself")
+	}
+
+	st.AddDependencies(taskID, deps...)
+	if cycle := findCycle(dependencyGraph(client, st, taskID)); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	if err := st.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("%s now depends on: %s\n", taskID, strings.Join(st.Dependencies[taskID], ", "))
+	return nil
+}
+
+// descriptionDependencies returns the task IDs listed on "depends-on:"
+// lines of a description, separated by commas or spaces.
+func descriptionDependencies(description string) []string {
+	var ids []string
+	for _, m := range dependsOnPattern.FindAllStringSubmatch(description, -1) {
+		for _, id := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// taskDependencies merges the dependencies recorded in the state file with
+// those declared in the task description.
+func taskDependencies(st *state.State, task api.Task) []string {
+	deps := slices.Clone(st.Dependencies[task.ID])
+	for _, id := range descriptionDependencies(task.Description) {
+		if !slices.Contains(deps, id) {
+			deps = append(deps, id)
+		}
+	}
+	return deps
+}
+
+// dependencyGraph returns the dependencies of taskID and of every task it
+// depends on, directly or not, as taskDependencies sees them. Tasks that
+// cannot be fetched contribute only the dependencies in the state file.
+func dependencyGraph(client *api.Client, st *state.State, taskID string) map[string][]string {
+	graph := map[string][]string{}
+	queue := []string{taskID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := graph[id]; ok {
+			continue
+		}
+		if task, err := client.GetTask(id); err == nil {
+			graph[id] = taskDependencies(st, *task)
+		} else {
+			graph[id] = st.Dependencies[id]
+		}
+		queue = append(queue, graph[id]...)
+	}
+	return graph
+}
+
+// orderByDependencies returns ids so that every task comes after the tasks
+// it depends on, keeping the given order where dependencies allow.
+// Dependencies outside ids are ignored. A cycle is an error.
+func orderByDependencies(ids []string, deps map[string][]string) ([]string, error) {
+	inQueue := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inQueue[id] = true
+	}
+
+	placed := make(map[string]bool, len(ids))
+	ordered := make([]string, 0, len(ids))
+	for len(ordered) < len(ids) {
+		progressed := false
+		for _, id := range ids {
+			if placed[id] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[id] {
+				if inQueue[dep] && !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[id] = true
+				ordered = append(ordered, id)
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			remaining := make(map[string][]string)
+			for _, id := range ids {
+				if !placed[id] {
+					remaining[id] = deps[id]
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle: %s", strings.Join(findCycle(remaining), " -> "))
+		}
+	}
+	return ordered, nil
+}
+
+// findCycle returns one dependency cycle as a path that starts and ends
+// with the same task, or nil.
+func findCycle(deps map[string][]string) []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := map[string]int{}
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch marks[id] {
+		case visiting:
+			start := slices.Index(path, id)
+			return append(slices.Clone(path[start:]), id)
+		case done:
+			return nil
+		}
+		marks[id] = visiting
+		path = append(path, id)
+		for _, dep := range deps[id] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[id] = done
+		return nil
+	}
+
+	keys := make([]string, 0, len(deps))
+	for id := range deps {
+		keys = append(keys, id)
+	}
+	slices.Sort(keys)
+	for _, id := range keys {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
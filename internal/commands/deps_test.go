@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"vkcli/internal/api"
+	"vkcli/internal/state"
+)
+
+func TestDepsAddRejectsCycleThroughDescription(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	s := &prServer{
+		tasks: map[string]api.Task{
+			"t1": {ID: "t1", Title: "API", Description: "Expose it.\ndepends-on: t2"},
+			"t2": {ID: "t2", Title: "Column"},
+			"t3": {ID: "t3", Title: "Docs", Description: "depends-on: t1"},
+		},
+	}
+	s.start(t)
+
+	// t2 -> t3 -> t1 (description) -> t2 (description)
+	err := runDepsChange([]string{"t2", "t3"}, true)
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("got %v, want a dependency cycle error", err)
+	}
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps := st.Dependencies["t2"]; len(deps) != 0 {
+		t.Errorf("cycle saved to the state file: %v", deps)
+	}
+
+	if err := runDepsChange([]string{"t3", "t2"}, true); err != nil {
+		t.Errorf("deps add t3 t2: %v", err)
+	}
+}
@@ -0,0 +1,406 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/jsonpatch"
+	"vkcli/internal/textdiff"
+)
+
+const diffUsage = "vkcli diff <attempt_id> [--stat | --name-only] [--remote] [--pager <cmd> | --no-pager]"
+
+// diffMode selects what vkcli diff prints.
+type diffMode int
+
+const (
+	diffPatch diffMode = iota
+	diffStat
+	diffNameOnly
+)
+
+// statBarWidth is the widest +/- bar of a diffstat line.
+const statBarWidth = 40
+
+// errSnapshotReady stops the diff stream once its initial snapshot is in.
+var errSnapshotReady = errors.New("snapshot ready")
+
+type DiffCommand struct{}
+
+func NewDiffCommand() Command {
+	return &DiffCommand{}
+}
+
+func (c *DiffCommand) Name() string {
+	return "diff"
+}
+
+func (c *DiffCommand) Usage() string {
+	return diffUsage
+}
+
+func (c *DiffCommand) Description() string {
+	return "attempt の変更差分を表示"
+}
+
+type diffOptions struct {
+	AttemptID string
+	Mode      diffMode
+	Remote    bool
+	Pager     string
+	NoPager   bool
+}
+
+func (c *DiffCommand) Run(args []string) error {
+	opts, err := parseDiffArgs(args)
+	if err != nil {
+		return err
+	}
+
+	attempt, err := apiClient().GetAttempt(opts.AttemptID)
+	if err != nil {
+		return err
+	}
+	text, err := attemptDiff(attempt, opts.Mode, opts.Remote)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		fmt.Printf("No changes in attempt %s.\n", attempt.ID)
+		return nil
+	}
+	return writeDiff(text, opts)
+}
+
+func parseDiffArgs(args []string) (diffOptions, error) {
+	var opts diffOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if v, ok, err := takeFlagValue(args, &i, "--pager"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			opts.Pager = v
+			continue
+		}
+		switch {
+		case arg == "--stat":
+			opts.Mode = diffStat
+		case arg == "--name-only":
+			opts.Mode = diffNameOnly
+		case arg == "--remote":
+			opts.Remote = true
+		case arg == "--server" || strings.HasPrefix(arg, "--server="):
+			return opts, fmt.Errorf("--server is a global flag; put it before the command: vkcli --server <url> diff ...")
+		case arg == "--no-pager":
+			opts.NoPager = true
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if opts.AttemptID != "" {
+				return opts, fmt.Errorf("Usage: %s", diffUsage)
+			}
+			opts.AttemptID = strings.TrimSpace(arg)
+		}
+	}
+	if opts.AttemptID == "" {
+		return opts, fmt.Errorf("Usage: %s", diffUsage)
+	}
+	if opts.NoPager && opts.Pager != "" {
+		return opts, fmt.Errorf("--pager and --no-pager cannot be combined")
+	}
+	return opts, nil
+}
+
+// attemptDiff returns the changes of an attempt against its base branch.
+// Unless forceRemote is set, git is run locally when the attempt's worktree
+// or the project's repository is on this machine; otherwise the diff comes
+// from the server.
+func attemptDiff(attempt *api.TaskAttempt, mode diffMode, forceRemote bool) (string, error) {
+	if !forceRemote {
+		if text, ok := localDiff(attempt, mode); ok {
+			return text, nil
+		}
+	}
+	files, err := fetchAttemptDiff(attempt.ID)
+	if err != nil {
+		return "", err
+	}
+	return formatFileDiffs(files, mode), nil
+}
+
+// localDiff runs git diff for an attempt. In its worktree, the working tree
+// is compared with the merge base so that uncommitted changes of a running
+// agent show up; in the project's repository the branch is compared with
+// base...branch. It reports false when neither is available.
+func localDiff(attempt *api.TaskAttempt, mode diffMode) (string, bool) {
+	if attempt.BaseBranch == "" {
+		return "", false
+	}
+	modeArgs := map[diffMode][]string{diffStat: {"--stat"}, diffNameOnly: {"--name-only"}}[mode]
+
+	if !attempt.WorktreeDeleted && isDir(attempt.ContainerRef) {
+		if out, err := gitDiff(attempt.ContainerRef, append(modeArgs, "--merge-base", attempt.BaseBranch)); err == nil {
+			return out, true
+		}
+	}
+
+	if attempt.Branch == "" {
+		return "", false
+	}
+	client := apiClient()
+	task, err := client.GetTask(attempt.TaskID)
+	if err != nil {
+		return "", false
+	}
+	project, err := client.GetProject(task.ProjectID)
+	if err != nil || !isDir(project.GitRepoPath) {
+		return "", false
+	}
+	out, err := gitDiff(project.GitRepoPath, append(modeArgs, attempt.BaseBranch+"..."+attempt.Branch))
+	if err != nil {
+		return "", false
+	}
+	return out, true
+}
+
+func gitDiff(dir string, args []string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "diff", "--no-color", "--no-ext-diff"}, args...)...)
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func isDir(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// fetchAttemptDiff reads the initial snapshot of an attempt's diff stream.
+// Servers that do not mark the end of the snapshot are read until they go
+// quiet.
+func fetchAttemptDiff(attemptID string) ([]api.FileDiff, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	idle := time.AfterFunc(snapshotIdle, cancel)
+	defer idle.Stop()
+
+	var doc interface{} = map[string]interface{}{"entries": map[string]interface{}{}}
+	err := apiClient().StreamAttemptDiff(ctx, attemptID, func(msg api.LogMessage) error {
+		idle.Reset(snapshotIdle)
+		if msg.Ready {
+			return errSnapshotReady
+		}
+		if len(msg.JSONPatch) == 0 {
+			return nil
+		}
+		patched, err := jsonpatch.Apply(doc, msg.JSONPatch)
+		if err != nil {
+			return fmt.Errorf("applying diff patch: %w", err)
+		}
+		doc = patched
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSnapshotReady) && !errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("decoding diff: the patched document is not an object")
+	}
+	raw, err := json.Marshal(obj["entries"])
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]struct {
+		Type    string       `json:"type"`
+		Content api.FileDiff `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("decoding diff: %w", err)
+	}
+
+	files := make([]api.FileDiff, 0, len(entries))
+	for _, key := range sortedKeys(entries) {
+		if e := entries[key]; strings.EqualFold(e.Type, "diff") {
+			files = append(files, e.Content)
+		}
+	}
+	return files, nil
+}
+
+// formatFileDiffs renders server diffs the way git diff would for mode.
+func formatFileDiffs(files []api.FileDiff, mode diffMode) string {
+	var b strings.Builder
+	switch mode {
+	case diffNameOnly:
+		for _, f := range files {
+			b.WriteString(fileDiffPath(f) + "\n")
+		}
+	case diffStat:
+		writeDiffStat(&b, files)
+	default:
+		for _, f := range files {
+			writeFileDiff(&b, f)
+		}
+	}
+	return b.String()
+}
+
+func fileDiffPath(f api.FileDiff) string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// fileDiffCounts returns the added and deleted lines of a file, computed
+// from its contents unless the server left them out.
+func fileDiffCounts(f api.FileDiff) (added, deleted int) {
+	if f.ContentOmitted {
+		return f.Additions, f.Deletions
+	}
+	_, added, deleted = textdiff.Unified("", "", f.OldContent, f.NewContent)
+	return added, deleted
+}
+
+func writeFileDiff(b *strings.Builder, f api.FileDiff) {
+	oldPath, newPath := f.OldPath, f.NewPath
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+	fmt.Fprintf(b, "diff --git a/%s b/%s\n", oldPath, newPath)
+
+	oldName, newName := "a/"+oldPath, "b/"+newPath
+	switch strings.ToLower(f.Change) {
+	case "added":
+		b.WriteString("new file\n")
+		oldName = "/dev/null"
+	case "deleted":
+		b.WriteString("deleted file\n")
+		newName = "/dev/null"
+	case "renamed":
+		fmt.Fprintf(b, "rename from %s\nrename to %s\n", oldPath, newPath)
+	case "copied":
+		fmt.Fprintf(b, "copy from %s\ncopy to %s\n", oldPath, newPath)
+	}
+
+	if f.ContentOmitted {
+		fmt.Fprintf(b, "Content omitted by the server (+%d -%d)\n", f.Additions, f.Deletions)
+		return
+	}
+	text, _, _ := textdiff.Unified(oldName, newName, f.OldContent, f.NewContent)
+	b.WriteString(text)
+}
+
+// writeDiffStat writes a git-style diffstat, scaling the bars down when a
+// file has more than statBarWidth changed lines.
+func writeDiffStat(b *strings.Builder, files []api.FileDiff) {
+	type stat struct {
+		path           string
+		added, deleted int
+	}
+	stats := make([]stat, 0, len(files))
+	nameWidth, maxChanged, totalAdded, totalDeleted := 0, 0, 0, 0
+	for _, f := range files {
+		added, deleted := fileDiffCounts(f)
+		s := stat{fileDiffPath(f), added, deleted}
+		stats = append(stats, s)
+		nameWidth = max(nameWidth, len(s.path))
+		maxChanged = max(maxChanged, added+deleted)
+		totalAdded += added
+		totalDeleted += deleted
+	}
+	countWidth := len(fmt.Sprint(maxChanged))
+
+	for _, s := range stats {
+		plus, minus := s.added, s.deleted
+		if maxChanged > statBarWidth {
+			plus = scaleStat(s.added, maxChanged)
+			minus = scaleStat(s.deleted, maxChanged)
+		}
+		fmt.Fprintf(b, " %-*s | %*d %s%s\n", nameWidth, s.path, countWidth, s.added+s.deleted,
+			strings.Repeat("+", plus), strings.Repeat("-", minus))
+	}
+	fmt.Fprintf(b, " %d %s changed, %d %s(+), %d %s(-)\n",
+		len(stats), plural(len(stats), "file", "files"),
+		totalAdded, plural(totalAdded, "insertion", "insertions"),
+		totalDeleted, plural(totalDeleted, "deletion", "deletions"))
+}
+
+// scaleStat scales n to the bar width, keeping at least one mark for any
+// change.
+func scaleStat(n, maxChanged int) int {
+	if n == 0 {
+		return 0
+	}
+	return max(n*statBarWidth/maxChanged, 1)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// writeDiff prints a diff, through the pager when one applies: --pager,
+// then the pager config key, then $PAGER, and "less -FRX" on a terminal.
+// Patches are colored for terminals unless the pager is delta, which does
+// its own highlighting.
+func writeDiff(text string, opts diffOptions) error {
+	pager := opts.Pager
+	if pager == "" && !opts.NoPager && isTerminal(os.Stdout) {
+		pager = cfg.Pager
+		if pager == "" {
+			pager = os.Getenv("PAGER")
+		}
+		if pager == "" {
+			pager = "less -FRX"
+		}
+	}
+
+	if opts.Mode == diffPatch && colorEnabled(os.Stdout) && !isDeltaPager(pager) {
+		text = colorizeDiff(text, painter(true))
+	}
+	if pager == "" || pager == "cat" {
+		fmt.Print(text)
+		return nil
+	}
+
+	// Run through the shell so that PAGER="less -R" works.
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pager %q failed: %w", pager, err)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is a terminal, regardless of NO_COLOR,
+// which only turns off colors.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func isDeltaPager(pager string) bool {
+	fields := strings.Fields(pager)
+	return len(fields) > 0 && filepath.Base(fields[0]) == "delta"
+}
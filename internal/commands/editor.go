@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editText opens $VISUAL / $EDITOR (falling back to vi) on a temporary file
+// containing initial and returns the saved contents.
+func editText(initial, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Run through the shell so that EDITOR="code --wait" works.
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
+
+// taskDocument is the Markdown form of a task used when editing in $EDITOR:
+// a front matter block with the title (and status) followed by the
+// description.
+type taskDocument struct {
+	Title       string
+	Status      string
+	Description string
+}
+
+func (d taskDocument) String() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", d.Title)
+	if d.Status != "" {
+		fmt.Fprintf(&b, "status: %s\n", d.Status)
+	}
+	b.WriteString("---\n")
+	b.WriteString(d.Description)
+	if !strings.HasSuffix(d.Description, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseTaskDocument reads a document written by taskDocument.String. A
+// missing front matter means the whole text is the description.
+func parseTaskDocument(text string) (taskDocument, error) {
+	var doc taskDocument
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		doc.Description = strings.TrimSpace(text)
+		return doc, nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(strings.TrimPrefix(text, "---\n")))
+	closed := false
+	var body []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if closed {
+			body = append(body, line)
+			continue
+		}
+		if strings.TrimSpace(line) == "---" {
+			closed = true
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return doc, fmt.Errorf("invalid front matter line: %q", line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			doc.Title = value
+		case "status":
+			doc.Status = value
+		default:
+			return doc, fmt.Errorf("unknown front matter key: %q", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return doc, err
+	}
+	if !closed {
+		return doc, fmt.Errorf("front matter is not closed with ---")
+	}
+	doc.Description = strings.TrimSpace(strings.Join(body, "\n"))
+	return doc, nil
+}
@@ -1,17 +1,15 @@
 package commands
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"strings"
 	"time"
+
+	"vkcli/internal/api"
 )
 
-const execUsage = "vkcli exec <task_id> [--executor <name>] [--base-branch <branch>]"
+const execUsage = "vkcli exec <task_id> [--executor <name>] [--variant <name>] [--base-branch <branch>] " +
+	"[--follow] [--json] [--timeout <duration>] [--detach] [--retries N] [--retry-executor <name>] [--retry-with-error]"
 
 type ExecCommand struct{}
 
@@ -31,161 +29,149 @@ func (c *ExecCommand) Description() string {
 	return "タスクを開始して監視"
 }
 
+// execOptions are the parsed arguments of the exec command.
+type execOptions struct {
+	TaskID   string
+	Settings execSettings
+	Retry    retryPolicy
+	watchOptions
+}
+
 func (c *ExecCommand) Run(args []string) error {
-	taskID, executor, baseBranch, err := parseExecArgs(args)
+	opts, err := parseExecArgs(args)
 	if err != nil {
 		return err
 	}
 
-	payload := map[string]interface{}{
-		"task_id":     taskID,
-		"base_branch": baseBranch,
-		"executor_profile_id": map[string]interface{}{
-			"executor": executor,
-		},
-	}
-	bodyBytes, err := json.Marshal(payload)
+	task, err := apiClient().GetTask(opts.TaskID)
 	if err != nil {
 		return err
 	}
-
-	resp, err := http.Post(fmt.Sprintf("%s/task-attempts", baseURL),
-		"application/json", bytes.NewReader(bodyBytes))
-	if err != nil {
+	if err := opts.Settings.resolve(task.ProjectID); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
+	if err := opts.Retry.resolve(); err != nil {
 		return err
 	}
 
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("failed to start attempt: status %d: %s",
-			resp.StatusCode, strings.TrimSpace(string(respBody)))
-	}
-
-	attemptID := extractAttemptID(respBody)
-	if attemptID == "" {
-		var fetchErr error
-		attemptID, fetchErr = waitForNewAttempt(taskID)
-		if fetchErr != nil {
-			return fetchErr
-		}
-	}
-	if attemptID == "" {
-		return fmt.Errorf("attempt id not found in response")
-	}
-	fmt.Printf("Started attempt: %s\n", attemptID)
-
-	lastStatusLen := 0
+	startedAt := time.Now()
+	progress := opts.progress()
+	settings := opts.Settings
+	var attempts []string
 	for {
-		time.Sleep(3 * time.Second)
-		status := getTaskStatus(taskID)
-		if status == "UNKNOWN" {
-			status = getAttemptStatus(attemptID)
+		var attemptID string
+		if len(attempts) == 0 {
+			attemptID, err = startAttempt(opts.TaskID, settings)
+		} else {
+			var warning error
+			attemptID, warning, err = opts.Retry.startRetry(opts.TaskID, attempts[len(attempts)-1], settings)
+			if warning != nil {
+				fmt.Fprintf(progress, "Warning: %v\n", warning)
+			}
 		}
-		statusLine := fmt.Sprintf("Status: %s", status)
-		padding := ""
-		if len(statusLine) < lastStatusLen {
-			padding = strings.Repeat(" ", lastStatusLen-len(statusLine))
+		if err != nil {
+			return err
 		}
-		fmt.Printf("\r%s%s", statusLine, padding)
-		lastStatusLen = len(statusLine)
-		if status == "INREVIEW" || status == "ERROR" {
-			fmt.Println()
-			break
+		attempts = append(attempts, attemptID)
+		if opts.Detach {
+			return reportDetached(opts.watchOptions, "Started attempt", opts.TaskID, attemptID, startedAt)
 		}
+		fmt.Fprintf(progress, "Started attempt: %s (%s)\n", attemptID, settings)
+
+		status, outcome, code, err := awaitAttempt(opts.TaskID, attemptID, nil, opts.watchOptions)
+		if err != nil {
+			return err
+		}
+		if !opts.Retry.shouldRetry(outcome, len(attempts)) {
+			return reportResult(opts.watchOptions, attemptResult{
+				TaskID:    opts.TaskID,
+				AttemptID: attemptID,
+				Attempts:  attempts,
+				Status:    status,
+				Outcome:   outcome,
+				ExitCode:  code,
+				StartedAt: startedAt,
+			})
+		}
+
+		settings = opts.Retry.settings(opts.Settings)
+		fmt.Fprintf(progress, "Attempt %s failed; retrying (%d/%d) with %s\n",
+			attemptID, len(attempts), opts.Retry.Retries, settings.Executor)
 	}
-	return nil
 }
 
-func parseExecArgs(args []string) (taskID, executor, baseBranch string, err error) {
-	executor = "CODEX"
-	baseBranch = "master"
+func parseExecArgs(args []string) (execOptions, error) {
+	var opts execOptions
 
 	if len(args) == 0 {
-		return "", "", "", fmt.Errorf("Usage: %s", execUsage)
+		return opts, fmt.Errorf("Usage: %s", execUsage)
 	}
 
 	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		switch {
-		case strings.HasPrefix(arg, "--executor="):
-			executor = strings.TrimSpace(strings.TrimPrefix(arg, "--executor="))
-		case arg == "--executor":
-			if i+1 >= len(args) {
-				return "", "", "", fmt.Errorf("--executor requires a value")
+		if ok, err := opts.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if ok, err := opts.Retry.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
 			}
-			executor = strings.TrimSpace(args[i+1])
-			i++
-		case strings.HasPrefix(arg, "--base-branch="):
-			baseBranch = strings.TrimSpace(strings.TrimPrefix(arg, "--base-branch="))
-		case arg == "--base-branch":
-			if i+1 >= len(args) {
-				return "", "", "", fmt.Errorf("--base-branch requires a value")
+			continue
+		}
+		if ok, err := opts.Settings.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
 			}
-			baseBranch = strings.TrimSpace(args[i+1])
-			i++
+			continue
+		}
+		arg := args[i]
+		switch {
 		case strings.HasPrefix(arg, "-"):
-			return "", "", "", fmt.Errorf("unknown flag: %s", arg)
+			return opts, fmt.Errorf("unknown flag: %s", arg)
 		default:
-			if taskID != "" {
-				return "", "", "", fmt.Errorf("multiple task IDs specified")
+			if opts.TaskID != "" {
+				return opts, fmt.Errorf("multiple task IDs specified")
 			}
-			taskID = strings.TrimSpace(arg)
+			opts.TaskID = strings.TrimSpace(arg)
 		}
 	}
 
-	if taskID == "" {
-		return "", "", "", fmt.Errorf("Usage: %s", execUsage)
+	if opts.TaskID == "" {
+		return opts, fmt.Errorf("Usage: %s", execUsage)
 	}
-	if executor == "" {
-		executor = "CODEX"
+	if err := opts.validate(); err != nil {
+		return opts, err
 	}
-	if baseBranch == "" {
-		baseBranch = "master"
+	if opts.Detach && opts.Retry.Retries > 0 {
+		return opts, fmt.Errorf("--detach cannot be combined with --retries")
 	}
-	return taskID, executor, baseBranch, nil
+	return opts, nil
 }
 
-func extractAttemptID(body []byte) string {
-	if len(bytes.TrimSpace(body)) == 0 {
-		return ""
+// startAttempt creates an attempt for a task and returns its ID.
+func startAttempt(taskID string, settings execSettings) (string, error) {
+	attempt, err := apiClient().CreateAttempt(api.CreateAttemptRequest{
+		TaskID:            taskID,
+		BaseBranch:        settings.BaseBranch,
+		ExecutorProfileID: settings.profile(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start attempt: %w", err)
 	}
 
-	var attemptWrap struct {
-		Success bool `json:"success"`
-		Data    struct {
-			ID string `json:"id"`
-		} `json:"data"`
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(body, &attemptWrap); err == nil {
-		if attemptWrap.Data.ID != "" {
-			return attemptWrap.Data.ID
-		}
-		if attemptWrap.ID != "" {
-			return attemptWrap.ID
+	attemptID := attempt.ID
+	if attemptID == "" {
+		attemptID, err = waitForNewAttempt(taskID)
+		if err != nil {
+			return "", err
 		}
 	}
-
-	var attemptMap map[string]interface{}
-	if err := json.Unmarshal(body, &attemptMap); err != nil {
-		return ""
-	}
-
-	if id, ok := attemptMap["id"].(string); ok && id != "" {
-		return id
-	}
-
-	if data, ok := attemptMap["data"].(map[string]interface{}); ok {
-		if id, ok := data["id"].(string); ok && id != "" {
-			return id
-		}
+	if attemptID == "" {
+		return "", fmt.Errorf("attempt id not found in response")
 	}
-	return ""
+	return attemptID, nil
 }
 
 func waitForNewAttempt(taskID string) (string, error) {
@@ -209,31 +195,15 @@ func waitForNewAttempt(taskID string) (string, error) {
 }
 
 func listTaskAttemptIDs(taskID string) ([]string, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/task-attempts?task_id=%s", baseURL, url.QueryEscape(taskID)))
+	attempts, err := apiClient().ListAttempts(taskID)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch task attempts: status %d: %s",
-			resp.StatusCode, strings.TrimSpace(string(body)))
-	}
-
-	var attemptWrapper struct {
-		Success bool                     `json:"success"`
-		Data    []map[string]interface{} `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&attemptWrapper); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch task attempts: %w", err)
 	}
 
-	ids := make([]string, 0, len(attemptWrapper.Data))
-	for _, attempt := range attemptWrapper.Data {
-		if id, ok := attempt["id"].(string); ok && id != "" {
-			ids = append(ids, id)
+	ids := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		if attempt.ID != "" {
+			ids = append(ids, attempt.ID)
 		}
 	}
 	return ids, nil
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"vkcli/internal/api"
+)
+
+// Built-in defaults, used when neither the command line, the config file
+// nor the project's repository say otherwise.
+const (
+	defaultExecutor   = "CODEX"
+	defaultBaseBranch = "master"
+)
+
+// execSettings are the executor profile and base branch of new attempts.
+type execSettings struct {
+	Executor   string
+	Variant    string
+	BaseBranch string
+}
+
+// parseFlag consumes args[*i] if it is an executor or base branch flag and
+// reports whether it did.
+func (s *execSettings) parseFlag(args []string, i *int) (bool, error) {
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"--executor", &s.Executor},
+		{"--variant", &s.Variant},
+		{"--base-branch", &s.BaseBranch},
+	} {
+		if v, ok, err := takeFlagValue(args, i, f.name); ok || err != nil {
+			if err != nil {
+				return true, err
+			}
+			*f.value = strings.TrimSpace(v)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s execSettings) String() string {
+	executor := s.Executor
+	if s.Variant != "" {
+		executor += "/" + s.Variant
+	}
+	return fmt.Sprintf("%s on %s", executor, s.BaseBranch)
+}
+
+// profile returns the executor profile to post with a new attempt.
+func (s execSettings) profile() api.ExecutorProfileID {
+	return api.ExecutorProfileID{Executor: s.Executor, Variant: s.Variant}
+}
+
+// resolve fills in the settings that were not given on the command line:
+// first from the project's section of the config file, then from the
+// top-level config keys, then by detecting the base branch from the
+// project's repository, and finally from the built-in defaults. The
+// executor is checked against the server's executor profiles.
+func (s *execSettings) resolve(projectID string) error {
+	defaults := cfg.ExecDefaultsFor(projectID)
+	if s.Executor == "" {
+		s.Executor = defaults.Executor
+		if s.Variant == "" {
+			s.Variant = defaults.Variant
+		}
+	}
+	if s.Executor == "" {
+		s.Executor = defaultExecutor
+	}
+	if s.BaseBranch == "" {
+		s.BaseBranch = defaults.BaseBranch
+	}
+	if s.BaseBranch == "" {
+		s.BaseBranch = detectBaseBranch(projectID)
+	}
+	if s.BaseBranch == "" {
+		s.BaseBranch = defaultBaseBranch
+	}
+
+	s.Executor = strings.ToUpper(s.Executor)
+	return validateExecutor(s.Executor, s.Variant)
+}
+
+// detectBaseBranch returns the branch checked out in the project's
+// repository: read with git when the repository is on this machine,
+// otherwise the current branch reported by the server. It returns "" when
+// neither works.
+func detectBaseBranch(projectID string) string {
+	client := apiClient()
+	if project, err := client.GetProject(projectID); err == nil && project.GitRepoPath != "" {
+		out, err := exec.Command("git", "-C", project.GitRepoPath, "symbolic-ref", "--short", "-q", "HEAD").Output()
+		if branch := strings.TrimSpace(string(out)); err == nil && branch != "" {
+			return branch
+		}
+	}
+
+	branches, err := client.ListBranches(projectID)
+	if err != nil {
+		return ""
+	}
+	for _, b := range branches {
+		if b.IsCurrent && !b.IsRemote {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// validateExecutor checks an executor and optional variant against the
+// server's executor profiles. Servers that do not report profiles are not
+// checked.
+func validateExecutor(executor, variant string) error {
+	info, err := apiClient().GetSystemInfo()
+	if err != nil || len(info.Executors) == 0 {
+		return nil
+	}
+
+	variants, ok := info.Executors[executor]
+	if !ok {
+		return fmt.Errorf("unknown executor %q (available: %s)", executor, strings.Join(sortedKeys(info.Executors), ", "))
+	}
+	if variant != "" && len(variants) > 0 {
+		if _, ok := variants[variant]; !ok {
+			return fmt.Errorf("unknown variant %q of %s (available: %s)", variant, executor, strings.Join(sortedKeys(variants), ", "))
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"vkcli/internal/api"
+)
+
+// Process exit codes. Scripts can rely on these to tell outcomes apart.
+const (
+	ExitOK          = 0 // success, e.g. the task reached INREVIEW
+	ExitFailure     = 1 // usage or unexpected errors
+	ExitAgentError  = 2 // the coding agent failed
+	ExitTimeout     = 3 // --timeout elapsed
+	ExitCancelled   = 4 // interrupted or stopped
+	ExitUnreachable = 5 // the server could not be reached
+)
+
+// ExitError makes main exit with Code. Err is printed when set; outcomes
+// that were already reported leave it nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode maps an error returned by a command to a process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if errors.Is(err, api.ErrUnreachable) {
+		return ExitUnreachable
+	}
+	return ExitFailure
+}
@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/conversation"
+)
+
+const exportUsage = "vkcli export <project_id> [--format md|json|csv] [--with-messages] " +
+	"[--out <file> | --out-dir <dir>]"
+
+type ExportCommand struct{}
+
+func NewExportCommand() Command {
+	return &ExportCommand{}
+}
+
+func (c *ExportCommand) Name() string {
+	return "export"
+}
+
+func (c *ExportCommand) Usage() string {
+	return exportUsage
+}
+
+func (c *ExportCommand) Description() string {
+	return "タスクを Markdown/JSON/CSV に書き出し"
+}
+
+// exportedTask is a task together with its attempts when --with-messages
+// is given.
+type exportedTask struct {
+	api.Task
+	Attempts []exportedAttempt `json:"attempts,omitempty"`
+}
+
+type exportedAttempt struct {
+	api.TaskAttempt
+	Processes []exportedProcess `json:"processes"`
+}
+
+type exportedProcess struct {
+	ID        string               `json:"id"`
+	RunReason string               `json:"run_reason"`
+	Status    string               `json:"status"`
+	Prompt    string               `json:"prompt,omitempty"`
+	Entries   []conversation.Entry `json:"entries"`
+}
+
+func (c *ExportCommand) Run(args []string) error {
+	var projectID, outFile, outDir string
+	format := "md"
+	withMessages := false
+	for i := 0; i < len(args); i++ {
+		handled := false
+		for _, f := range []struct {
+			name   string
+			target *string
+		}{
+			{"--format", &format},
+			{"--out", &outFile},
+			{"--out-dir", &outDir},
+		} {
+			v, ok, err := takeFlagValue(args, &i, f.name)
+			if err != nil {
+				return err
+			}
+			if ok {
+				*f.target = v
+				handled = true
+				break
+			}
+		}
+		if handled {
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--with-messages":
+			withMessages = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case projectID != "":
+			return fmt.Errorf("multiple project IDs specified")
+		default:
+			projectID = strings.TrimSpace(arg)
+		}
+	}
+	if projectID == "" {
+		return fmt.Errorf("Usage: %s", exportUsage)
+	}
+
+	format = strings.ToLower(format)
+	switch format {
+	case "md", "markdown":
+		format = "md"
+	case "json", "csv":
+	default:
+		return fmt.Errorf("unknown export format %q (expected md, json or csv)", format)
+	}
+	if outFile != "" && outDir != "" {
+		return fmt.Errorf("--out and --out-dir are mutually exclusive")
+	}
+	if format == "csv" && (outDir != "" || withMessages) {
+		return fmt.Errorf("csv export writes a single file without messages")
+	}
+
+	client := apiClient()
+	tasks, err := client.ListTasks(projectID)
+	if err != nil {
+		return err
+	}
+
+	exported := make([]exportedTask, len(tasks))
+	for i, t := range tasks {
+		exported[i].Task = t
+		if !withMessages {
+			continue
+		}
+		if exported[i].Attempts, err = exportAttempts(client, t.ID); err != nil {
+			return fmt.Errorf("task %s: %w", t.ID, err)
+		}
+	}
+
+	if outDir != "" {
+		return exportTree(outDir, format, projectID, exported)
+	}
+
+	var w io.Writer = os.Stdout
+	if outFile != "" {
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeExport(w, format, projectID, exported); err != nil {
+		return err
+	}
+	if outFile != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d tasks to %s\n", len(exported), outFile)
+	}
+	return nil
+}
+
+func exportAttempts(client *api.Client, taskID string) ([]exportedAttempt, error) {
+	attempts, err := client.ListAttempts(taskID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]exportedAttempt, len(attempts))
+	for i, attempt := range attempts {
+		result[i].TaskAttempt = attempt
+		processes, err := client.ListExecutionProcesses(attempt.ID)
+		if err != nil {
+			return nil, err
+		}
+		result[i].Processes = make([]exportedProcess, len(processes))
+		for j, p := range processes {
+			entries, err := fetchNormalizedLogs(p.ID)
+			if err != nil {
+				return nil, fmt.Errorf("process %s: %w", p.ID, err)
+			}
+			result[i].Processes[j] = exportedProcess{
+				ID:        p.ID,
+				RunReason: p.RunReason,
+				Status:    p.Status,
+				Prompt:    strings.TrimSpace(p.ExecutorAction.Typ.Prompt),
+				Entries:   entries,
+			}
+		}
+	}
+	return result, nil
+}
+
+// exportTree writes an index file plus one file per task below dir.
+func exportTree(dir, format, projectID string, tasks []exportedTask) error {
+	if err := os.MkdirAll(filepath.Join(dir, "tasks"), 0o755); err != nil {
+		return err
+	}
+
+	write := func(path string, fn func(io.Writer) error) error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	index := filepath.Join(dir, "index."+format)
+	err := write(index, func(w io.Writer) error {
+		if format == "json" {
+			plain := make([]api.Task, len(tasks))
+			for i, t := range tasks {
+				plain[i] = t.Task
+			}
+			return writeExportJSON(w, plain)
+		}
+		fmt.Fprintf(w, "# Project %s\n\n", projectID)
+		fmt.Fprintln(w, "| ID | Title | Status | Updated |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, t := range tasks {
+			fmt.Fprintf(w, "| [%s](tasks/%s.md) | %s | %s | %s |\n",
+				t.ID, t.ID, markdownCell(t.Title), t.Status, formatTime(t.UpdatedAt))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		path := filepath.Join(dir, "tasks", t.ID+"."+format)
+		err := write(path, func(w io.Writer) error {
+			if format == "json" {
+				return writeExportJSON(w, t)
+			}
+			writeTaskMarkdown(w, t, "#")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d tasks to %s\n", len(tasks), dir)
+	return nil
+}
+
+func writeExport(w io.Writer, format, projectID string, tasks []exportedTask) error {
+	switch format {
+	case "json":
+		return writeExportJSON(w, tasks)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "title", "description", "status", "created_at", "updated_at"})
+		for _, t := range tasks {
+			cw.Write([]string{t.ID, t.Title, t.Description, t.Status,
+				formatRFC3339(t.CreatedAt), formatRFC3339(t.UpdatedAt)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	fmt.Fprintf(w, "# Project %s\n\n", projectID)
+	for _, t := range tasks {
+		writeTaskMarkdown(w, t, "##")
+	}
+	return nil
+}
+
+func writeExportJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// writeTaskMarkdown renders a task with heading level h ("#" or "##").
+func writeTaskMarkdown(w io.Writer, t exportedTask, h string) {
+	fmt.Fprintf(w, "%s %s\n\n", h, t.Title)
+	fmt.Fprintf(w, "- ID: `%s`\n", t.ID)
+	fmt.Fprintf(w, "- Status: %s\n", t.Status)
+	fmt.Fprintf(w, "- Created: %s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(w, "- Updated: %s\n\n", formatTime(t.UpdatedAt))
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		fmt.Fprintf(w, "%s\n\n", desc)
+	}
+
+	for _, a := range t.Attempts {
+		fmt.Fprintf(w, "%s# Attempt `%s`\n\n", h, a.ID)
+		fmt.Fprintf(w, "- Executor: %s\n", a.Executor)
+		fmt.Fprintf(w, "- Branch: `%s` (base `%s`)\n", a.Branch, a.BaseBranch)
+		fmt.Fprintf(w, "- Created: %s\n\n", formatTime(a.CreatedAt))
+		for _, p := range a.Processes {
+			fmt.Fprintf(w, "%s## Process `%s` (%s, %s)\n\n", h, p.ID, p.RunReason, p.Status)
+			if p.Prompt != "" {
+				fmt.Fprintf(w, "**Prompt:**\n\n%s\n\n", quoteMarkdown(p.Prompt))
+			}
+			writeLogEntriesMarkdown(w, p.Entries)
+		}
+	}
+}
+
+func writeLogEntriesMarkdown(w io.Writer, entries []conversation.Entry) {
+	for _, entry := range entries {
+		content := strings.TrimSpace(entry.Content)
+		switch entry.Type() {
+		case conversation.UserMessage:
+			fmt.Fprintf(w, "%s\n\n", quoteMarkdown(content))
+		case conversation.AssistantMessage:
+			fmt.Fprintf(w, "**Assistant:**\n\n%s\n\n", content)
+		case conversation.Thinking:
+			fmt.Fprintf(w, "_Thinking:_ %s\n\n", content)
+		case conversation.ToolUse:
+			tool := entry.EntryType.ToolName
+			if tool == "" {
+				tool = "tool"
+			}
+			fmt.Fprintf(w, "- 🔧 **%s** `%s`\n\n", tool, strings.ReplaceAll(content, "`", "'"))
+			if action := entry.EntryType.ActionType; action != nil && action.Action == "file_edit" {
+				for _, change := range action.Changes {
+					if change.UnifiedDiff != "" {
+						fmt.Fprintf(w, "```diff\n%s\n```\n\n", strings.TrimRight(change.UnifiedDiff, "\n"))
+					}
+				}
+			}
+		case conversation.SystemMessage, conversation.ErrorMessage:
+			fmt.Fprintf(w, "_%s:_ %s\n\n", strings.TrimSuffix(entry.Type(), "_message"), content)
+		}
+	}
+}
+
+func quoteMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+)
+
+const followupUsage = `vkcli followup <attempt_id> ["<prompt>" | --prompt-file <path>] ` +
+	"[--follow] [--json] [--timeout <duration>] [--detach]"
+
+// followupComment marks the instruction lines shown in the editor; they are
+// removed from the prompt.
+const followupComment = "# vkcli: "
+
+type FollowupCommand struct{}
+
+func NewFollowupCommand() Command {
+	return &FollowupCommand{}
+}
+
+func (c *FollowupCommand) Name() string {
+	return "followup"
+}
+
+func (c *FollowupCommand) Usage() string {
+	return followupUsage
+}
+
+func (c *FollowupCommand) Description() string {
+	return "既存の attempt に追加指示を送って監視"
+}
+
+// followupOptions are the parsed arguments of the followup command.
+type followupOptions struct {
+	AttemptID  string
+	Prompt     string
+	PromptFile string
+	// HasPrompt is set when the prompt was given as an argument, even if
+	// empty.
+	HasPrompt bool
+	watchOptions
+}
+
+func (c *FollowupCommand) Run(args []string) error {
+	opts, err := parseFollowupArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client := apiClient()
+	attempt, err := client.GetAttempt(opts.AttemptID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch attempt: %w", err)
+	}
+
+	prompt := opts.Prompt
+	switch {
+	case opts.PromptFile != "":
+		prompt, err = readDescriptionFile(opts.PromptFile)
+	case !opts.HasPrompt:
+		prompt, err = editFollowupPrompt()
+	}
+	if err != nil {
+		return err
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return fmt.Errorf("empty prompt, nothing sent")
+	}
+
+	// Processes that already exist are not streamed again by --follow.
+	existing := map[string]bool{}
+	processes, err := client.ListExecutionProcesses(attempt.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		existing[p.ID] = true
+	}
+
+	startedAt := time.Now()
+	process, err := client.FollowUp(attempt.ID, api.FollowUpRequest{Prompt: prompt})
+	if err != nil {
+		return fmt.Errorf("failed to send follow-up: %w", err)
+	}
+	if opts.Detach {
+		return reportDetached(opts.watchOptions, "Sent follow-up to attempt", attempt.TaskID, attempt.ID, startedAt)
+	}
+	fmt.Fprintf(opts.progress(), "Sent follow-up: process %s\n", process.ID)
+
+	return watchAttempt(attempt.TaskID, attempt.ID, startedAt, existing, opts.watchOptions)
+}
+
+func parseFollowupArgs(args []string) (followupOptions, error) {
+	var opts followupOptions
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		if ok, err := opts.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--prompt-file"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			opts.PromptFile = v
+			continue
+		}
+		if arg := args[i]; strings.HasPrefix(arg, "-") {
+			return opts, fmt.Errorf("unknown flag: %s", arg)
+		}
+		positional = append(positional, args[i])
+	}
+
+	if len(positional) == 0 || len(positional) > 2 {
+		return opts, fmt.Errorf("Usage: %s", followupUsage)
+	}
+	opts.AttemptID = strings.TrimSpace(positional[0])
+	if len(positional) == 2 {
+		opts.Prompt = positional[1]
+		opts.HasPrompt = true
+	}
+	if opts.HasPrompt && opts.PromptFile != "" {
+		return opts, fmt.Errorf("give the prompt either as an argument or with --prompt-file, not both")
+	}
+	if err := opts.validate(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// editFollowupPrompt opens $EDITOR to write a prompt.
+func editFollowupPrompt() (string, error) {
+	initial := "\n" +
+		followupComment + "Write the follow-up prompt above. Lines starting with\n" +
+		followupComment + "\"" + strings.TrimSpace(followupComment) + "\" are ignored; an empty prompt aborts.\n"
+	edited, err := editText(initial, "vkcli-followup-*.md")
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(edited, "\n") {
+		if !strings.HasPrefix(line, followupComment) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
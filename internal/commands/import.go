@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/importer"
+)
+
+const importUsage = "vkcli import <project_id> <file> [--from md|yaml|csv] [--dry-run] [--yes]"
+
+type ImportCommand struct{}
+
+func NewImportCommand() Command {
+	return &ImportCommand{}
+}
+
+func (c *ImportCommand) Name() string {
+	return "import"
+}
+
+func (c *ImportCommand) Usage() string {
+	return importUsage
+}
+
+func (c *ImportCommand) Description() string {
+	return "Markdown/YAML/CSV からタスクを一括作成"
+}
+
+func (c *ImportCommand) Run(args []string) error {
+	var positional []string
+	var from string
+	dryRun, assumeYes := false, false
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--from"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			from = strings.ToLower(v)
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+		case arg == "-":
+			positional = append(positional, arg)
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("Usage: %s", importUsage)
+	}
+	projectID, path := positional[0], positional[1]
+
+	if path == "-" && !dryRun && !assumeYes {
+		return fmt.Errorf("--yes is required when reading from stdin")
+	}
+	if from == "" {
+		if path == "-" {
+			return fmt.Errorf("--from is required when reading from stdin")
+		}
+		var err error
+		if from, err = importer.DetectFormat(path); err != nil {
+			return err
+		}
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	items, err := importer.Parse(r, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for i, item := range items {
+		if item.Status != "" {
+			if items[i].Status, err = parseTaskStatus(item.Status); err != nil {
+				return fmt.Errorf("%q: %w", item.Title, err)
+			}
+		}
+	}
+
+	client := apiClient()
+	existing, err := client.ListTasks(projectID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, t := range existing {
+		seen[titleKey(t.Title)] = true
+	}
+	var toCreate []importer.Item
+	fmt.Printf("Import plan for project %s (%s):\n", projectID, path)
+	for _, item := range items {
+		key := titleKey(item.Title)
+		if seen[key] {
+			fmt.Printf("  = %s (duplicate title, skipped)\n", item.Title)
+			continue
+		}
+		seen[key] = true
+		toCreate = append(toCreate, item)
+
+		fmt.Printf("  + %s", item.Title)
+		if item.Status != "" && item.Status != "todo" {
+			fmt.Printf(" [%s]", item.Status)
+		}
+		fmt.Println()
+		if item.Description != "" {
+			for _, line := range strings.Split(item.Description, "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+	}
+	fmt.Printf("\n%d to create, %d skipped.\n", len(toCreate), len(items)-len(toCreate))
+
+	if dryRun || len(toCreate) == 0 {
+		return nil
+	}
+	if !assumeYes {
+		ok, err := confirm(fmt.Sprintf("Create %d tasks?", len(toCreate)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	for _, item := range toCreate {
+		task, err := client.CreateTask(api.CreateTaskRequest{
+			ProjectID:   projectID,
+			Title:       item.Title,
+			Description: item.Description,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %q: %w", item.Title, err)
+		}
+		if item.Status != "" && item.Status != task.Status {
+			_, err := client.UpdateTask(task.ID, api.UpdateTaskRequest{
+				Title:       task.Title,
+				Description: task.Description,
+				Status:      item.Status,
+			})
+			if err != nil {
+				return fmt.Errorf("created %s but failed to set status: %w", task.ID, err)
+			}
+		}
+		fmt.Printf("Created task: %s  %s\n", task.ID, task.Title)
+	}
+	return nil
+}
+
+// titleKey is the comparison key used to detect duplicate titles.
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
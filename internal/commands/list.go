@@ -1,12 +1,17 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/output"
 )
 
+const listUsage = "vkcli list <project_id> [--status s1,s2] [--title-match <regex>] " +
+	"[--created-since <t>] [--updated-since <t>] [--sort created|updated|title|status] " +
+	"[--limit N] [--reverse] [--output <fmt>]"
+
 type ListCommand struct{}
 
 func NewListCommand() Command {
@@ -18,7 +23,7 @@ func (c *ListCommand) Name() string {
 }
 
 func (c *ListCommand) Usage() string {
-	return "vkcli list <project_id>"
+	return listUsage
 }
 
 func (c *ListCommand) Description() string {
@@ -26,29 +31,40 @@ func (c *ListCommand) Description() string {
 }
 
 func (c *ListCommand) Run(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("Usage: vkcli list <project_id>")
-	}
-	projectID := args[0]
-	url := fmt.Sprintf("%s/tasks?project_id=%s", baseURL, projectID)
-	resp, err := http.Get(url)
+	args, format, err := parseOutputFlags(args)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	var wrapper struct {
-		Success   bool                     `json:"success"`
-		Data      []map[string]interface{} `json:"data"`
-		ErrorData interface{}              `json:"error_data"`
-		Message   interface{}              `json:"message"`
+	var filter taskFilter
+	projectID := ""
+	for i := 0; i < len(args); i++ {
+		handled, err := filter.parseFlag(args, &i)
+		if err != nil {
+			return err
+		}
+		switch {
+		case handled:
+		case strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("unknown flag: %s", args[i])
+		case projectID != "":
+			return fmt.Errorf("multiple project IDs specified")
+		default:
+			projectID = args[i]
+		}
+	}
+	if projectID == "" {
+		return fmt.Errorf("Usage: %s", listUsage)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+	tasks, err := apiClient().ListTasks(projectID)
+	if err != nil {
 		return err
 	}
-
-	tasks := wrapper.Data
+	tasks = filter.apply(tasks)
+	if !format.IsTable() {
+		return writeOutput(format, tasks, taskColumns(tasks))
+	}
 	if len(tasks) == 0 {
 		fmt.Println("No tasks found for this project.")
 		return nil
@@ -58,7 +74,17 @@ func (c *ListCommand) Run(args []string) error {
 	fmt.Println(strings.Repeat("-", 92))
 	for _, t := range tasks {
 		fmt.Printf("%-38s  %-40s  %-10s\n",
-			t["id"], t["title"], t["status"])
+			t.ID, t.Title, t.Status)
 	}
 	return nil
 }
+
+func taskColumns(tasks []api.Task) output.Columns {
+	cols := output.Columns{Headers: []string{"id", "title", "status", "created_at", "updated_at"}}
+	for _, t := range tasks {
+		cols.Rows = append(cols.Rows, []string{
+			t.ID, t.Title, t.Status, formatRFC3339(t.CreatedAt), formatRFC3339(t.UpdatedAt),
+		})
+	}
+	return cols
+}
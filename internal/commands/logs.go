@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/conversation"
+)
+
+const logsUsage = "vkcli logs <attempt_id|task_id> [--process <id>] [-f|--follow] " +
+	"[--since <t>] [--type t1,t2] [--raw]"
+
+// snapshotIdle is how long a running process may stay silent before a
+// non-follow logs call stops waiting for more history.
+const snapshotIdle = 2 * time.Second
+
+type LogsCommand struct{}
+
+func NewLogsCommand() Command {
+	return &LogsCommand{}
+}
+
+func (c *LogsCommand) Name() string {
+	return "logs"
+}
+
+func (c *LogsCommand) Usage() string {
+	return logsUsage
+}
+
+func (c *LogsCommand) Description() string {
+	return "実行ログを表示"
+}
+
+// logFilter restricts which entries are printed.
+type logFilter struct {
+	Types map[string]bool
+	Since time.Time
+}
+
+func (f logFilter) keep(e conversation.Entry) bool {
+	if len(f.Types) > 0 && !f.Types[e.Type()] {
+		return false
+	}
+	if !f.Since.IsZero() && e.Timestamp != nil && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+type logsOptions struct {
+	Target    string
+	ProcessID string
+	Follow    bool
+	Raw       bool
+	Filter    logFilter
+}
+
+func (c *LogsCommand) Run(args []string) error {
+	opts, err := parseLogsArgs(args)
+	if err != nil {
+		return err
+	}
+	client := apiClient()
+
+	if opts.ProcessID != "" {
+		process, err := client.GetExecutionProcess(opts.ProcessID)
+		if err != nil {
+			return err
+		}
+		return streamProcessLogs(*process, opts)
+	}
+
+	taskID, attemptID, process, err := resolveLogsTarget(client, opts.Target)
+	if err != nil {
+		return err
+	}
+	if process != nil {
+		return streamProcessLogs(*process, opts)
+	}
+
+	header := os.Stdout
+	if opts.Raw {
+		header = os.Stderr
+	}
+	fmt.Fprintf(header, "Attempt ID: %s\n", attemptID)
+
+	if opts.Follow {
+		_, err := followAttempt(context.Background(), os.Stdout, taskID, attemptID, nil, func(p api.ExecutionProcess) error {
+			if skipProcess(p, opts.Filter) {
+				return nil
+			}
+			return streamProcessLogs(p, opts)
+		})
+		return err
+	}
+
+	processes, err := client.ListExecutionProcesses(attemptID)
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		fmt.Fprintln(header, "(no execution processes found)")
+		return nil
+	}
+	for _, p := range processes {
+		if skipProcess(p, opts.Filter) {
+			continue
+		}
+		if err := streamProcessLogs(p, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseLogsArgs(args []string) (logsOptions, error) {
+	var opts logsOptions
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--process"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			opts.ProcessID = v
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--since"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			if opts.Filter.Since, err = parseSince(v, time.Now()); err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--type"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			opts.Filter.Types = map[string]bool{}
+			for _, t := range strings.Split(v, ",") {
+				if t = strings.ToLower(strings.TrimSpace(t)); t == "" {
+					continue
+				}
+				if !slices.Contains(conversation.Types, t) {
+					return opts, fmt.Errorf("unknown entry type %q (valid: %s)", t, strings.Join(conversation.Types, ", "))
+				}
+				opts.Filter.Types[t] = true
+			}
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "-f" || arg == "--follow":
+			opts.Follow = true
+		case arg == "--raw":
+			opts.Raw = true
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("unknown flag: %s", arg)
+		case opts.Target != "":
+			return opts, fmt.Errorf("multiple targets specified")
+		default:
+			opts.Target = strings.TrimSpace(arg)
+		}
+	}
+	if opts.Target == "" && opts.ProcessID == "" {
+		return opts, fmt.Errorf("Usage: %s", logsUsage)
+	}
+	return opts, nil
+}
+
+// resolveLogsTarget accepts a task ID (its latest attempt is used), an
+// attempt ID or an execution process ID.
+func resolveLogsTarget(client *api.Client, id string) (taskID, attemptID string, process *api.ExecutionProcess, err error) {
+	if task, err := client.GetTask(id); err == nil {
+		attempts, err := client.ListAttempts(task.ID)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if len(attempts) == 0 {
+			return "", "", nil, fmt.Errorf("task %s has no attempts", task.ID)
+		}
+		return task.ID, attempts[len(attempts)-1].ID, nil, nil
+	}
+	if attempt, err := client.GetAttempt(id); err == nil {
+		return attempt.TaskID, attempt.ID, nil, nil
+	}
+	if process, err := client.GetExecutionProcess(id); err == nil {
+		return "", "", process, nil
+	}
+	return "", "", nil, fmt.Errorf("%s is not a task, attempt or execution process", id)
+}
+
+// skipProcess drops processes that completed before --since.
+func skipProcess(p api.ExecutionProcess, filter logFilter) bool {
+	return !filter.Since.IsZero() && p.CompletedAt != nil && p.CompletedAt.Before(filter.Since)
+}
+
+// streamProcessLogs prints the logs of one process. Unless following, a
+// running process is only read until its history has been replayed.
+func streamProcessLogs(p api.ExecutionProcess, opts logsOptions) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var idle *time.Timer
+	if !opts.Follow && p.Status == "running" {
+		idle = time.AfterFunc(snapshotIdle, cancel)
+		defer idle.Stop()
+	}
+
+	var err error
+	if opts.Raw {
+		err = dumpRawLogs(ctx, p.ID, os.Stdout, idle)
+	} else {
+		printProcessHeader(os.Stdout, p)
+		err = followNormalizedLogs(ctx, p.ID, os.Stdout, logStreamOptions{Filter: opts.Filter, Idle: idle})
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// dumpRawLogs writes every websocket message of a process as one JSON line.
+func dumpRawLogs(ctx context.Context, processID string, w io.Writer, idle *time.Timer) error {
+	return apiClient().StreamNormalizedLogs(ctx, processID, func(msg api.LogMessage) error {
+		if idle != nil {
+			idle.Reset(snapshotIdle)
+		}
+		_, err := fmt.Fprintf(w, "%s\n", bytes.TrimSpace(msg.Raw))
+		return err
+	})
+}
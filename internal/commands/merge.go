@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"vkcli/internal/api"
+)
+
+const mergeUsage = "vkcli merge <attempt_id> [--yes]"
+
+type MergeCommand struct{}
+
+func NewMergeCommand() Command {
+	return &MergeCommand{}
+}
+
+func (c *MergeCommand) Name() string {
+	return "merge"
+}
+
+func (c *MergeCommand) Usage() string {
+	return mergeUsage
+}
+
+func (c *MergeCommand) Description() string {
+	return "attempt のブランチをマージしてタスクを DONE にする"
+}
+
+func (c *MergeCommand) Run(args []string) error {
+	var attemptID string
+	yes := false
+	for _, arg := range args {
+		switch {
+		case arg == "--yes" || arg == "-y":
+			yes = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if attemptID != "" {
+				return fmt.Errorf("Usage: %s", mergeUsage)
+			}
+			attemptID = strings.TrimSpace(arg)
+		}
+	}
+	if attemptID == "" {
+		return fmt.Errorf("Usage: %s", mergeUsage)
+	}
+
+	client := apiClient()
+	attempt, err := client.GetAttempt(attemptID)
+	if err != nil {
+		return err
+	}
+	return mergeAttempt(client, attempt, yes)
+}
+
+// mergeAttempt prints the branch status of an attempt, merges its branch
+// after confirmation (unless yes is set) and moves its task to DONE.
+func mergeAttempt(client *api.Client, attempt *api.TaskAttempt, yes bool) error {
+	status, err := client.GetBranchStatus(attempt.ID)
+	if err != nil {
+		return err
+	}
+	printBranchStatus(attempt, status)
+
+	if status.IsRebaseInProgress || len(status.ConflictedFiles) > 0 {
+		return fmt.Errorf("attempt %s has unresolved conflicts; resolve them before merging", attempt.ID)
+	}
+	if status.CommitsAhead == 0 {
+		return fmt.Errorf("branch %s has no commits ahead of %s; nothing to merge", attempt.Branch, baseBranchName(attempt, status))
+	}
+	if status.HasUncommittedChanges {
+		fmt.Println("Warning: the worktree has uncommitted changes, which are not merged.")
+	}
+
+	if !yes {
+		ok, err := confirm(fmt.Sprintf("Merge %s into %s?", attempt.Branch, baseBranchName(attempt, status)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := client.MergeAttempt(attempt.ID); err != nil {
+		if conflict, ok := gitConflict(err); ok {
+			return reportConflict(attempt, conflict)
+		}
+		return err
+	}
+	fmt.Printf("Merged %s into %s.\n", attempt.Branch, baseBranchName(attempt, status))
+
+	if err := markTaskDone(client, attempt.TaskID); err != nil {
+		return fmt.Errorf("merged, but could not update task %s: %w", attempt.TaskID, err)
+	}
+	fmt.Printf("Task %s is now DONE.\n", attempt.TaskID)
+	return nil
+}
+
+// markTaskDone moves a task to DONE unless the server already did.
+func markTaskDone(client *api.Client, taskID string) error {
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if normalizeStatusString(task.Status) == "DONE" {
+		return nil
+	}
+	_, err = client.UpdateTask(taskID, api.UpdateTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      "done",
+	})
+	return err
+}
+
+func baseBranchName(attempt *api.TaskAttempt, status *api.BranchStatus) string {
+	if status != nil && status.BaseBranchName != "" {
+		return status.BaseBranchName
+	}
+	return attempt.BaseBranch
+}
+
+// printBranchStatus prints how an attempt's branch compares with its base
+// branch.
+func printBranchStatus(attempt *api.TaskAttempt, status *api.BranchStatus) {
+	fmt.Printf("Branch:       %s -> %s\n", attempt.Branch, baseBranchName(attempt, status))
+	fmt.Printf("Ahead/Behind: %d ahead, %d behind\n", status.CommitsAhead, status.CommitsBehind)
+	if status.HasUncommittedChanges {
+		fmt.Println("Uncommitted:  yes")
+	}
+	if status.IsRebaseInProgress {
+		fmt.Println("Rebase:       in progress")
+	}
+	if len(status.ConflictedFiles) > 0 {
+		fmt.Printf("Conflicts:    %s\n", strings.Join(status.ConflictedFiles, ", "))
+	}
+}
+
+// gitConflict reports whether err is a server error about merge conflicts
+// and returns its details.
+func gitConflict(err error) (*api.GitOperationError, bool) {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) || len(apiErr.ErrorData) == 0 {
+		return nil, false
+	}
+	var opErr api.GitOperationError
+	if json.Unmarshal(apiErr.ErrorData, &opErr) != nil {
+		return nil, false
+	}
+	if opErr.Type != "merge_conflicts" && len(opErr.ConflictedFiles) == 0 {
+		return nil, false
+	}
+	if opErr.Message == "" {
+		opErr.Message = apiErr.Message
+	}
+	return &opErr, true
+}
+
+// reportConflict prints the conflicted files of a failed merge or rebase
+// and returns the error the command ends with.
+func reportConflict(attempt *api.TaskAttempt, conflict *api.GitOperationError) error {
+	op := conflict.Op
+	if op == "" {
+		op = "git operation"
+	}
+	fmt.Printf("The %s of %s stopped with conflicts", op, attempt.Branch)
+	if conflict.Message != "" {
+		fmt.Printf(": %s", conflict.Message)
+	}
+	fmt.Println()
+	for _, f := range conflict.ConflictedFiles {
+		fmt.Printf("  %s\n", f)
+	}
+	if attempt.ContainerRef != "" {
+		fmt.Printf("Resolve them in %s, or ask the agent with `vkcli followup %s`.\n", attempt.ContainerRef, attempt.ID)
+	}
+	return &ExitError{Code: ExitFailure}
+}
@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"vkcli/internal/api"
+)
+
+// pollInterval is how often a running attempt is polled.
+var pollInterval = 3 * time.Second
+
+// maxUnreachablePolls is how many consecutive polls may fail to reach the
+// server before monitoring gives up.
+const maxUnreachablePolls = 20
+
+// isFinalStatus reports whether a task status ends the monitoring of an
+// attempt.
+func isFinalStatus(status string) bool {
+	switch status {
+	case "INREVIEW", "ERROR", "DONE", "CANCELLED":
+		return true
+	}
+	return false
+}
+
+// attemptFinished reports whether an attempt whose task has status is done:
+// the status must be final and the attempt no longer running, because the
+// status can be left over from an earlier attempt or run. A failed check
+// counts as still running, to be retried on the next poll.
+func attemptFinished(attemptID, status string) bool {
+	if !isFinalStatus(status) {
+		return false
+	}
+	running, err := attemptRunning(attemptID)
+	return err == nil && !running
+}
+
+// attemptRunning reports whether an attempt is still working: while any of
+// its processes runs, and before its coding agent process exists unless an
+// earlier process (such as a setup script) failed and ended the chain. A
+// task can be in a final status from an earlier attempt while the new one
+// is starting.
+func attemptRunning(attemptID string) (bool, error) {
+	processes, err := apiClient().ListExecutionProcesses(attemptID)
+	if err != nil {
+		return false, err
+	}
+	settled := false
+	for _, p := range processes {
+		if p.Status == "running" {
+			return true, nil
+		}
+		if p.RunReason == "codingagent" || p.Status == "failed" || p.Status == "killed" {
+			settled = true
+		}
+	}
+	return !settled, nil
+}
+
+// errInterrupted is the cancellation cause of a monitorContext when SIGINT
+// or SIGTERM arrives.
+var errInterrupted = errors.New("interrupted")
+
+// monitorContext returns a context for monitoring an attempt. It is
+// cancelled with cause errInterrupted on the first SIGINT or SIGTERM, and
+// with context.DeadlineExceeded once timeout elapses if timeout is positive.
+// After the first signal the default handling is restored, so a second
+// Ctrl-C exits immediately.
+func monitorContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelTimeout := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancelCause(errInterrupted)
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, func() {
+		cancelTimeout()
+		cancelCause(nil)
+	}
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// monitorOptions tune monitorAttempt.
+type monitorOptions struct {
+	// Follow streams the conversation instead of a status line.
+	Follow bool
+	// Skip lists execution processes that Follow does not stream.
+	Skip map[string]bool
+}
+
+// monitorAttempt waits until the task of an attempt reaches a final status
+// and the attempt has stopped running, and returns that status. Without Follow it keeps a single "Status: ..."
+// line up to date on out; with Follow it streams the conversation of every
+// execution process of the attempt as it is produced. It returns ctx's
+// error once ctx is done.
+func monitorAttempt(ctx context.Context, out io.Writer, taskID, attemptID string, opts monitorOptions) (string, error) {
+	if opts.Follow {
+		return followAttempt(ctx, out, taskID, attemptID, opts.Skip, func(p api.ExecutionProcess) error {
+			printProcessHeader(out, p)
+			return followNormalizedLogs(ctx, p.ID, out, logStreamOptions{})
+		})
+	}
+
+	lastStatusLen := 0
+	var poller statusPoller
+	for {
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			fmt.Fprintln(out)
+			return "", err
+		}
+		status, err := poller.poll(taskID, attemptID)
+		if err != nil {
+			fmt.Fprintln(out)
+			return status, err
+		}
+		statusLine := fmt.Sprintf("Status: %s", status)
+		padding := ""
+		if len(statusLine) < lastStatusLen {
+			padding = strings.Repeat(" ", lastStatusLen-len(statusLine))
+		}
+		fmt.Fprintf(out, "\r%s%s", statusLine, padding)
+		lastStatusLen = len(statusLine)
+		if attemptFinished(attemptID, status) {
+			fmt.Fprintln(out)
+			return status, nil
+		}
+	}
+}
+
+// statusPoller fetches attempt statuses and gives up once the server has
+// been unreachable for maxUnreachablePolls polls in a row.
+type statusPoller struct {
+	unreachable int
+}
+
+func (p *statusPoller) poll(taskID, attemptID string) (string, error) {
+	status, err := getTaskStatusByID(taskID)
+	if err == nil {
+		p.unreachable = 0
+		return status, nil
+	}
+	if errors.Is(err, api.ErrUnreachable) {
+		p.unreachable++
+		if p.unreachable >= maxUnreachablePolls {
+			return "UNKNOWN", &ExitError{Code: ExitUnreachable, Err: err}
+		}
+		return "UNKNOWN", nil
+	}
+	p.unreachable = 0
+	return getAttemptStatus(attemptID), nil
+}
+
+// followAttempt calls stream for each execution process of an attempt in
+// creation order, except those in skip, moving on to the next process once
+// stream returns, until the task reaches a final status with the attempt no
+// longer running, which is reported on out, or ctx is done.
+func followAttempt(ctx context.Context, out io.Writer, taskID, attemptID string, skip map[string]bool, stream func(api.ExecutionProcess) error) (string, error) {
+	client := apiClient()
+	followed := map[string]bool{}
+	for id := range skip {
+		followed[id] = true
+	}
+	var poller statusPoller
+	for {
+		processes, err := client.ListExecutionProcesses(attemptID)
+		if err != nil {
+			if !errors.Is(err, api.ErrUnreachable) {
+				return "", err
+			}
+			processes = nil
+		}
+
+		streamed := false
+		for _, p := range processes {
+			if followed[p.ID] {
+				continue
+			}
+			followed[p.ID] = true
+			streamed = true
+
+			if err := stream(p); err != nil {
+				return "", err
+			}
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+		}
+
+		status, err := poller.poll(taskID, attemptID)
+		if err != nil {
+			return status, err
+		}
+		if attemptFinished(attemptID, status) {
+			fmt.Fprintf(out, "\nStatus: %s\n", status)
+			return status, nil
+		}
+		if !streamed {
+			if err := sleepContext(ctx, pollInterval); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+func printProcessHeader(w io.Writer, p api.ExecutionProcess) {
+	fmt.Fprintf(w, "\n🔹 Process ID: %s (%s)\n", p.ID, p.RunReason)
+	if prompt := strings.TrimSpace(p.ExecutorAction.Typ.Prompt); prompt != "" {
+		fmt.Fprintf(w, "🧑 User Prompt:\n%s\n", prompt)
+	}
+}
+
+// attemptOutcome classifies how an attempt ended, based on the final task
+// status and the status of its latest coding agent process.
+func attemptOutcome(attemptID, status string) (outcome string, code int) {
+	switch status {
+	case "ERROR":
+		return "agent_error", ExitAgentError
+	case "CANCELLED":
+		return "cancelled", ExitCancelled
+	}
+
+	processes, err := apiClient().ListExecutionProcesses(attemptID)
+	if err == nil {
+		for i := len(processes) - 1; i >= 0; i-- {
+			if processes[i].RunReason != "codingagent" {
+				continue
+			}
+			switch processes[i].Status {
+			case "failed":
+				return "agent_error", ExitAgentError
+			case "killed":
+				return "cancelled", ExitCancelled
+			}
+			break
+		}
+	}
+	return "success", ExitOK
+}
+
+// stopRunningProcesses stops every running execution process of an attempt
+// and returns the IDs of the processes it stopped.
+func stopRunningProcesses(attemptID string) ([]string, error) {
+	client := apiClient()
+	processes, err := client.ListExecutionProcesses(attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stopped []string
+	for _, p := range processes {
+		if p.Status != "running" {
+			continue
+		}
+		if err := client.StopExecutionProcess(p.ID); err != nil {
+			return stopped, fmt.Errorf("failed to stop process %s: %w", p.ID, err)
+		}
+		stopped = append(stopped, p.ID)
+	}
+	return stopped, nil
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"vkcli/internal/api"
+)
+
+// retryServer stands in for vibe-kanban while a retry starts: the task is
+// still in ERROR from the failed attempt and the new attempt has no coding
+// agent process yet. Each task poll moves the new attempt on: its coding
+// agent runs from the third poll and has finished, with the task in
+// INREVIEW, from the fifth.
+type retryServer struct {
+	mu    sync.Mutex
+	polls int
+}
+
+func (s *retryServer) start(t *testing.T) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.polls++
+		status := "error"
+		switch {
+		case s.polls >= 5:
+			status = "inreview"
+		case s.polls >= 3:
+			status = "inprogress"
+		}
+		reply(w, api.Task{ID: r.PathValue("id"), Status: status})
+	})
+	mux.HandleFunc("GET /api/execution-processes", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var processes []api.ExecutionProcess
+		switch {
+		case s.polls >= 5:
+			processes = []api.ExecutionProcess{{ID: "p2", RunReason: "codingagent", Status: "completed"}}
+		case s.polls >= 3:
+			processes = []api.ExecutionProcess{{ID: "p2", RunReason: "codingagent", Status: "running"}}
+		}
+		reply(w, processes)
+	})
+	startServer(t, mux)
+
+	saved := pollInterval
+	pollInterval = time.Millisecond
+	t.Cleanup(func() { pollInterval = saved })
+}
+
+func TestMonitorAttemptIgnoresLeftoverStatus(t *testing.T) {
+	s := &retryServer{}
+	s.start(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	status, err := monitorAttempt(ctx, io.Discard, "t1", "a2", monitorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "INREVIEW" {
+		t.Errorf("got status %s, want INREVIEW", status)
+	}
+	if outcome, _ := attemptOutcome("a2", status); outcome != "success" {
+		t.Errorf("got outcome %s, want success", outcome)
+	}
+}
+
+func TestFollowAttemptIgnoresLeftoverStatus(t *testing.T) {
+	s := &retryServer{}
+	s.start(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var streamed []string
+	status, err := followAttempt(ctx, io.Discard, "t1", "a2", nil, func(p api.ExecutionProcess) error {
+		streamed = append(streamed, p.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "INREVIEW" {
+		t.Errorf("got status %s, want INREVIEW", status)
+	}
+	if len(streamed) != 1 || streamed[0] != "p2" {
+		t.Errorf("streamed %v, want the new coding agent process", streamed)
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"vkcli/internal/output"
+)
+
+var defaultOutput = output.Format{Mode: output.Table}
+
+// SetDefaultOutput installs the format selected by the global --output and
+// --format flags.
+func SetDefaultOutput(f output.Format) {
+	defaultOutput = f
+}
+
+// parseOutputFlags strips --output/-o and --format from args. Values given
+// here override the global flags.
+func parseOutputFlags(args []string) (rest []string, format output.Format, err error) {
+	mode, tmpl := "", ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "--output="):
+			mode = strings.TrimPrefix(arg, "--output=")
+		case arg == "--output" || arg == "-o":
+			if i+1 >= len(args) {
+				return nil, output.Format{}, fmt.Errorf("%s requires a value", arg)
+			}
+			mode = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			tmpl = strings.TrimPrefix(arg, "--format=")
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return nil, output.Format{}, fmt.Errorf("--format requires a value")
+			}
+			tmpl = args[i+1]
+			i++
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	if mode == "" && tmpl == "" {
+		return rest, defaultOutput, nil
+	}
+	format, err = output.Parse(mode, tmpl)
+	return rest, format, err
+}
+
+// writeOutput prints v in a machine-readable format to stdout.
+func writeOutput(format output.Format, v interface{}, cols output.Columns) error {
+	return output.Write(os.Stdout, format, v, cols)
+}
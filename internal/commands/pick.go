@@ -1,15 +1,15 @@
 package commands
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"os/exec"
 	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/config"
 )
 
 type PickCommand struct{}
@@ -37,7 +37,7 @@ func (c *PickCommand) Run(args []string) error {
 
 	withMessages := len(args) > 0 && args[0] == "--with-messages"
 
-	projects, err := fetchProjects()
+	projects, err := apiClient().ListProjects()
 	if err != nil {
 		return err
 	}
@@ -72,9 +72,15 @@ func (c *PickCommand) Run(args []string) error {
 	}
 
 	previewCmd := fmt.Sprintf("%s show {1} --with-messages", shellQuote(execPath))
+	if cfg.ServerSource == config.SourceFlag {
+		// The preview runs in a fresh process that only sees the env and the
+		// config file, so forward an explicit --server.
+		previewCmd = fmt.Sprintf("%s --server %s show {1} --with-messages",
+			shellQuote(execPath), shellQuote(cfg.Server))
+	}
 
 	for {
-		tasks, err := fetchTasks(projectID)
+		tasks, err := apiClient().ListTasks(projectID)
 		if err != nil {
 			return err
 		}
@@ -140,53 +146,6 @@ func (c *PickCommand) Run(args []string) error {
 	}
 }
 
-type project struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
-type task struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Status string `json:"status"`
-}
-
-func fetchProjects() ([]project, error) {
-	resp, err := http.Get(baseURL + "/projects")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var wrapper struct {
-		Success bool      `json:"success"`
-		Data    []project `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
-		return nil, err
-	}
-	return wrapper.Data, nil
-}
-
-func fetchTasks(projectID string) ([]task, error) {
-	values := url.Values{}
-	values.Set("project_id", projectID)
-	resp, err := http.Get(fmt.Sprintf("%s/tasks?%s", baseURL, values.Encode()))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var wrapper struct {
-		Success bool   `json:"success"`
-		Data    []task `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
-		return nil, err
-	}
-	return wrapper.Data, nil
-}
-
 func runFzf(prompt string, lines []string, extraArgs ...string) (string, string, bool, error) {
 	args := []string{"--prompt", prompt, "--no-multi"}
 	expectUsed := false
@@ -294,7 +253,7 @@ func parseFzfOutput(raw []byte, expectUsed bool) (selection string, key string)
 	return selection, ""
 }
 
-func formatProjectHeader(projects []project, currentIndex int) string {
+func formatProjectHeader(projects []api.Project, currentIndex int) string {
 	var b strings.Builder
 	b.WriteString(sectionDivider("Actions"))
 	b.WriteString("\n")
@@ -314,7 +273,7 @@ func formatProjectHeader(projects []project, currentIndex int) string {
 	return strings.TrimRight(b.String(), "\n")
 }
 
-func findProjectIndex(projects []project, id string) int {
+func findProjectIndex(projects []api.Project, id string) int {
 	for i, p := range projects {
 		if p.ID == id {
 			return i
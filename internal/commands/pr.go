@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/conversation"
+)
+
+const prCreateUsage = `vkcli pr create <attempt_id> [--title <title>] ` +
+	`[--body <text> | --body-file <path> | --body-from-summary] [--base <branch>] [--draft]`
+
+type PRCommand struct{}
+
+func NewPRCommand() Command {
+	return &PRCommand{}
+}
+
+func (c *PRCommand) Name() string {
+	return "pr"
+}
+
+func (c *PRCommand) Usage() string {
+	return prCreateUsage
+}
+
+func (c *PRCommand) Description() string {
+	return "attempt のブランチをプッシュしてプルリクエストを作成"
+}
+
+func (c *PRCommand) Run(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("Usage: %s", prCreateUsage)
+	}
+	switch args[0] {
+	case "create":
+		return runPRCreate(args[1:])
+	default:
+		return fmt.Errorf("unknown pr subcommand: %s", args[0])
+	}
+}
+
+type prCreateOptions struct {
+	AttemptID   string
+	Title       string
+	Body        string
+	BodyFile    string
+	FromSummary bool
+	Base        string
+	Draft       bool
+}
+
+func runPRCreate(args []string) error {
+	opts, err := parsePRCreateArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client := apiClient()
+	attempt, err := client.GetAttempt(opts.AttemptID)
+	if err != nil {
+		return err
+	}
+	task, err := client.GetTask(attempt.TaskID)
+	if err != nil {
+		return err
+	}
+
+	req := api.CreatePRRequest{
+		Title:      opts.Title,
+		Body:       opts.Body,
+		BaseBranch: opts.Base,
+		Draft:      opts.Draft,
+	}
+	if req.Title == "" {
+		req.Title = task.Title
+	}
+	if req.BaseBranch == "" {
+		req.BaseBranch = attempt.BaseBranch
+	}
+	switch {
+	case opts.BodyFile != "":
+		if req.Body, err = readDescriptionFile(opts.BodyFile); err != nil {
+			return err
+		}
+	case opts.FromSummary:
+		if req.Body, err = finalAssistantMessage(attempt.ID); err != nil {
+			return err
+		}
+		if req.Body == "" {
+			return fmt.Errorf("attempt %s has no final assistant message to use as the body", attempt.ID)
+		}
+	case req.Body == "":
+		if req.Body, err = summaryPRBody(attempt.ID, task); err != nil {
+			return err
+		}
+	}
+
+	if err := client.PushAttempt(attempt.ID); err != nil {
+		return fmt.Errorf("pushing %s: %w", attempt.Branch, err)
+	}
+	fmt.Printf("Pushed branch: %s\n", attempt.Branch)
+
+	prURL, err := client.CreatePR(attempt.ID, req)
+	if err != nil {
+		return fmt.Errorf("creating pull request: %w", err)
+	}
+	kind := "Pull request"
+	if opts.Draft {
+		kind = "Draft pull request"
+	}
+	fmt.Printf("%s: %s\n", kind, prURL)
+	return nil
+}
+
+func parsePRCreateArgs(args []string) (prCreateOptions, error) {
+	var opts prCreateOptions
+	sources := 0
+	for i := 0; i < len(args); i++ {
+		matched := false
+		for _, f := range []struct {
+			name   string
+			value  *string
+			source bool
+		}{
+			{"--title", &opts.Title, false},
+			{"--body", &opts.Body, true},
+			{"--body-file", &opts.BodyFile, true},
+			{"--base", &opts.Base, false},
+		} {
+			v, ok, err := takeFlagValue(args, &i, f.name)
+			if err != nil {
+				return opts, err
+			}
+			if ok {
+				*f.value = v
+				if f.source {
+					sources++
+				}
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch arg := args[i]; {
+		case arg == "--body-from-summary":
+			opts.FromSummary = true
+			sources++
+		case arg == "--draft":
+			opts.Draft = true
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if opts.AttemptID != "" {
+				return opts, fmt.Errorf("Usage: %s", prCreateUsage)
+			}
+			opts.AttemptID = strings.TrimSpace(arg)
+		}
+	}
+	if opts.AttemptID == "" {
+		return opts, fmt.Errorf("Usage: %s", prCreateUsage)
+	}
+	if sources > 1 {
+		return opts, fmt.Errorf("use only one of --body, --body-file and --body-from-summary")
+	}
+	return opts, nil
+}
+
+// summaryPRBody returns the default pull request body: the final assistant
+// message of the attempt, which is usually the agent's summary of its
+// changes. Attempts without one fall back to the task description.
+func summaryPRBody(attemptID string, task *api.Task) (string, error) {
+	summary, err := finalAssistantMessage(attemptID)
+	if err != nil || summary != "" {
+		return summary, err
+	}
+	return prTaskDescription(task.Description), nil
+}
+
+// prTaskDescription strips the lines that only vkcli reads, such as
+// depends-on and retry error context, from a task description.
+func prTaskDescription(description string) string {
+	if i := strings.Index(description, retryErrorMarker); i >= 0 {
+		description = description[:i]
+	}
+	description = dependsOnPattern.ReplaceAllString(description, "")
+	return strings.TrimSpace(description)
+}
+
+// finalAssistantMessage returns the last assistant message of the latest
+// coding agent process of an attempt that has one, or "".
+func finalAssistantMessage(attemptID string) (string, error) {
+	processes, err := apiClient().ListExecutionProcesses(attemptID)
+	if err != nil {
+		return "", err
+	}
+	for i := len(processes) - 1; i >= 0; i-- {
+		if processes[i].RunReason != "codingagent" {
+			continue
+		}
+		entries, err := fetchNormalizedLogs(processes[i].ID)
+		if err != nil {
+			return "", err
+		}
+		for j := len(entries) - 1; j >= 0; j-- {
+			if entries[j].Type() == conversation.AssistantMessage {
+				if text := strings.TrimSpace(entries[j].Content); text != "" {
+					return text, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
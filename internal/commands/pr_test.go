@@ -0,0 +1,272 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"vkcli/internal/api"
+	"vkcli/internal/config"
+)
+
+// prServer stands in for vibe-kanban: it serves attempts, tasks and the
+// execution processes of attempts, streams the normalized logs of each
+// process as JSON Patch messages, one message per entry, and records the
+// pull requests it is asked to open.
+type prServer struct {
+	attempts  map[string]api.TaskAttempt
+	tasks     map[string]api.Task
+	processes map[string][]api.ExecutionProcess
+	logs      map[string][][]interface{}
+
+	pushed  []string
+	created []api.CreatePRRequest
+}
+
+func (s *prServer) start(t *testing.T) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/task-attempts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, s.attempts[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /api/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, s.tasks[r.PathValue("id")])
+	})
+	mux.HandleFunc("POST /api/task-attempts/{id}/push", func(w http.ResponseWriter, r *http.Request) {
+		s.pushed = append(s.pushed, r.PathValue("id"))
+		reply(w, nil)
+	})
+	mux.HandleFunc("POST /api/task-attempts/{id}/pr", func(w http.ResponseWriter, r *http.Request) {
+		var req api.CreatePRRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		s.created = append(s.created, req)
+		reply(w, "https://github.com/example/repo/pull/1")
+	})
+	mux.HandleFunc("GET /api/execution-processes", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, s.processes[r.URL.Query().Get("task_attempt_id")])
+	})
+	upgrader := websocket.Upgrader{}
+	mux.HandleFunc("GET /api/execution-processes/{id}/normalized-logs/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, patch := range s.logs[r.PathValue("id")] {
+			conn.WriteJSON(map[string]interface{}{"JsonPatch": patch})
+		}
+		conn.WriteJSON(map[string]interface{}{"finished": true})
+	})
+	startServer(t, mux)
+}
+
+// startServer serves handler for the rest of the test and points the
+// commands at it.
+func startServer(t *testing.T, handler http.Handler) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	saved := cfg
+	SetConfig(&config.Config{Server: server.URL})
+	t.Cleanup(func() { SetConfig(saved) })
+}
+
+// reply writes data in the response envelope of the API.
+func reply(w http.ResponseWriter, data interface{}) {
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+func addEntry(index int, entryType, content string) []interface{} {
+	return patchEntry("add", index, entryType, content)
+}
+
+func patchEntry(op string, index int, entryType, content string) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"op":   op,
+		"path": "/entries/" + strconv.Itoa(index),
+		"value": map[string]interface{}{
+			"type": "NORMALIZED_ENTRY",
+			"content": map[string]interface{}{
+				"entry_type": map[string]interface{}{"type": entryType},
+				"content":    content,
+			},
+		},
+	}}
+}
+
+func TestFinalAssistantMessage(t *testing.T) {
+	s := &prServer{
+		processes: map[string][]api.ExecutionProcess{
+			"a1": {
+				{ID: "setup", RunReason: "setupscript"},
+				{ID: "agent", RunReason: "codingagent"},
+				{ID: "cleanup", RunReason: "cleanupscript"},
+			},
+			"a2": {
+				{ID: "first", RunReason: "codingagent"},
+				{ID: "followup", RunReason: "codingagent"},
+			},
+			"a3": {
+				{ID: "quiet", RunReason: "codingagent"},
+			},
+		},
+		logs: map[string][][]interface{}{
+			"setup": {addEntry(0, "assistant_message", "not an agent")},
+			"agent": {
+				addEntry(0, "user_message", "Expose the column"),
+				addEntry(1, "assistant_message", "Looking at the code."),
+				addEntry(2, "tool_use", "cargo test"),
+				addEntry(3, "assistant_message", "Exposed"),
+				patchEntry("replace", 3, "assistant_message", "  Exposed the column and added tests.\n"),
+			},
+			"cleanup": {addEntry(0, "assistant_message", "not an agent either")},
+			"first":   {addEntry(0, "assistant_message", "Summary of the first run.")},
+			"followup": {
+				addEntry(0, "user_message", "Also fix the docs"),
+				addEntry(1, "error_message", "rate limited"),
+			},
+			"quiet": {addEntry(0, "tool_use", "ls")},
+		},
+	}
+	s.start(t)
+
+	tests := []struct {
+		attemptID string
+		want      string
+	}{
+		{"a1", "Exposed the column and added tests."},
+		{"a2", "Summary of the first run."},
+		{"a3", ""},
+		{"none", ""},
+	}
+	for _, tt := range tests {
+		got, err := finalAssistantMessage(tt.attemptID)
+		if err != nil {
+			t.Fatalf("finalAssistantMessage(%s): %v", tt.attemptID, err)
+		}
+		if got != tt.want {
+			t.Errorf("finalAssistantMessage(%s) = %q, want %q", tt.attemptID, got, tt.want)
+		}
+	}
+}
+
+func TestSummaryPRBody(t *testing.T) {
+	s := &prServer{
+		processes: map[string][]api.ExecutionProcess{
+			"summarized": {{ID: "p1", RunReason: "codingagent"}},
+			"silent":     {{ID: "p2", RunReason: "codingagent"}},
+		},
+		logs: map[string][][]interface{}{
+			"p1": {addEntry(0, "assistant_message", "Added the endpoint.")},
+			"p2": {addEntry(0, "tool_use", "ls")},
+		},
+	}
+	s.start(t)
+	task := &api.Task{Description: "Add the endpoint.\ndepends-on: t0\n\n" + retryErrorMarker + "\nit failed"}
+
+	got, err := summaryPRBody("summarized", task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Added the endpoint."; got != want {
+		t.Errorf("with a final message: got %q, want %q", got, want)
+	}
+
+	got, err = summaryPRBody("silent", task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Add the endpoint."; got != want {
+		t.Errorf("without a final message: got %q, want %q", got, want)
+	}
+}
+
+func TestPRTaskDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"plain", "Fix the login bug.", "Fix the login bug."},
+		{"empty", "", ""},
+		{"depends-on lines", "depends-on: t1, t2\nFix it.\n  Depends-On: t3\n", "Fix it."},
+		{"retry error context", "Fix it.\n\n" + retryErrorMarker + "\n```\npanic\n```\n", "Fix it."},
+		{"only vkcli lines", "depends-on: t1\n" + retryErrorMarker + "\nerror", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prTaskDescription(tt.description); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPRCreate(t *testing.T) {
+	s := &prServer{
+		attempts: map[string]api.TaskAttempt{
+			"a1": {ID: "a1", TaskID: "t1", Branch: "vk/a1-expose", BaseBranch: "main"},
+			"a2": {ID: "a2", TaskID: "t1", Branch: "vk/a2-expose", BaseBranch: "main"},
+		},
+		tasks: map[string]api.Task{
+			"t1": {ID: "t1", Title: "Expose the column", Description: "Expose it.\ndepends-on: t0"},
+		},
+		processes: map[string][]api.ExecutionProcess{
+			"a1": {{ID: "p1", RunReason: "codingagent"}},
+			"a2": {{ID: "p2", RunReason: "codingagent"}},
+		},
+		logs: map[string][][]interface{}{
+			"p1": {addEntry(0, "assistant_message", "Exposed the column.")},
+			"p2": {addEntry(0, "tool_use", "ls")},
+		},
+	}
+	s.start(t)
+
+	tests := []struct {
+		args []string
+		want api.CreatePRRequest
+	}{
+		{
+			[]string{"a1"},
+			api.CreatePRRequest{Title: "Expose the column", Body: "Exposed the column.", BaseBranch: "main"},
+		},
+		{
+			[]string{"a1", "--body-from-summary", "--draft"},
+			api.CreatePRRequest{Title: "Expose the column", Body: "Exposed the column.", BaseBranch: "main", Draft: true},
+		},
+		{
+			[]string{"a2", "--title", "Expose it", "--base", "develop"},
+			api.CreatePRRequest{Title: "Expose it", Body: "Expose it.", BaseBranch: "develop"},
+		},
+		{
+			[]string{"a2", "--body", "Custom body"},
+			api.CreatePRRequest{Title: "Expose the column", Body: "Custom body", BaseBranch: "main"},
+		},
+	}
+	for _, tt := range tests {
+		s.created = nil
+		if err := runPRCreate(tt.args); err != nil {
+			t.Fatalf("pr create %v: %v", tt.args, err)
+		}
+		if len(s.created) != 1 || s.created[0] != tt.want {
+			t.Errorf("pr create %v sent %+v, want %+v", tt.args, s.created, tt.want)
+		}
+	}
+
+	if len(s.pushed) != len(tests) {
+		t.Errorf("pushed %v, want one push per pull request", s.pushed)
+	}
+
+	s.created = nil
+	if err := runPRCreate([]string{"a2", "--body-from-summary"}); err == nil {
+		t.Error("--body-from-summary without a final message: expected an error")
+	}
+	if len(s.created) != 0 {
+		t.Errorf("opened a pull request without a body: %+v", s.created)
+	}
+}
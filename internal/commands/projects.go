@@ -1,10 +1,11 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
+
+	"vkcli/internal/api"
+	"vkcli/internal/output"
 )
 
 type ProjectsCommand struct{}
@@ -18,7 +19,7 @@ func (c *ProjectsCommand) Name() string {
 }
 
 func (c *ProjectsCommand) Usage() string {
-	return "vkcli projects"
+	return "vkcli projects [--output <fmt>]"
 }
 
 func (c *ProjectsCommand) Description() string {
@@ -26,24 +27,21 @@ func (c *ProjectsCommand) Description() string {
 }
 
 func (c *ProjectsCommand) Run(args []string) error {
-	resp, err := http.Get(baseURL + "/projects")
+	args, format, err := parseOutputFlags(args)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	var wrapper struct {
-		Success   bool                     `json:"success"`
-		Data      []map[string]interface{} `json:"data"`
-		ErrorData interface{}              `json:"error_data"`
-		Message   interface{}              `json:"message"`
+	if len(args) > 0 {
+		return fmt.Errorf("Usage: vkcli projects [--output <fmt>]")
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+	projects, err := apiClient().ListProjects()
+	if err != nil {
 		return err
 	}
-
-	projects := wrapper.Data
+	if !format.IsTable() {
+		return writeOutput(format, projects, projectColumns(projects))
+	}
 	if len(projects) == 0 {
 		fmt.Println("No projects found.")
 		return nil
@@ -52,7 +50,15 @@ func (c *ProjectsCommand) Run(args []string) error {
 	fmt.Printf("%-38s  %-40s\n", "PROJECT ID", "NAME")
 	fmt.Println(strings.Repeat("-", 80))
 	for _, p := range projects {
-		fmt.Printf("%-38s  %-40s\n", p["id"], p["name"])
+		fmt.Printf("%-38s  %-40s\n", p.ID, p.Name)
 	}
 	return nil
 }
+
+func projectColumns(projects []api.Project) output.Columns {
+	cols := output.Columns{Headers: []string{"id", "name", "git_repo_path"}}
+	for _, p := range projects {
+		cols.Rows = append(cols.Rows, []string{p.ID, p.Name, p.GitRepoPath})
+	}
+	return cols
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"vkcli/internal/api"
+)
+
+const rebaseUsage = "vkcli rebase <attempt_id> [--onto <branch>]"
+
+type RebaseCommand struct{}
+
+func NewRebaseCommand() Command {
+	return &RebaseCommand{}
+}
+
+func (c *RebaseCommand) Name() string {
+	return "rebase"
+}
+
+func (c *RebaseCommand) Usage() string {
+	return rebaseUsage
+}
+
+func (c *RebaseCommand) Description() string {
+	return "attempt のブランチをリベース"
+}
+
+func (c *RebaseCommand) Run(args []string) error {
+	var attemptID, onto string
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--onto"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			onto = v
+			continue
+		}
+		switch arg := args[i]; {
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if attemptID != "" {
+				return fmt.Errorf("Usage: %s", rebaseUsage)
+			}
+			attemptID = strings.TrimSpace(arg)
+		}
+	}
+	if attemptID == "" {
+		return fmt.Errorf("Usage: %s", rebaseUsage)
+	}
+
+	client := apiClient()
+	attempt, err := client.GetAttempt(attemptID)
+	if err != nil {
+		return err
+	}
+	if onto != "" {
+		if err := checkBranchExists(client, attempt.TaskID, onto); err != nil {
+			return err
+		}
+	}
+
+	if err := client.RebaseAttempt(attempt.ID, api.RebaseRequest{NewBaseBranch: onto}); err != nil {
+		conflict, ok := gitConflict(err)
+		if !ok {
+			return err
+		}
+		if status, statusErr := client.GetBranchStatus(attempt.ID); statusErr == nil {
+			printBranchStatus(attempt, status)
+		}
+		return reportConflict(attempt, conflict)
+	}
+
+	status, err := client.GetBranchStatus(attempt.ID)
+	if err != nil {
+		return err
+	}
+	if status.IsRebaseInProgress || len(status.ConflictedFiles) > 0 {
+		printBranchStatus(attempt, status)
+		return reportConflict(attempt, &api.GitOperationError{Op: "rebase", ConflictedFiles: status.ConflictedFiles})
+	}
+
+	base := onto
+	if base == "" {
+		base = baseBranchName(attempt, status)
+	}
+	fmt.Printf("Rebased %s onto %s.\n", attempt.Branch, base)
+	printBranchStatus(attempt, status)
+	return nil
+}
+
+// checkBranchExists verifies that branch exists in the repository of a
+// task's project. Servers that do not list branches are not checked.
+func checkBranchExists(client *api.Client, taskID, branch string) error {
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	branches, err := client.ListBranches(task.ProjectID)
+	if err != nil || len(branches) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(branches))
+	for _, b := range branches {
+		if b.Name == branch {
+			return nil
+		}
+		if !b.IsRemote {
+			names = append(names, b.Name)
+		}
+	}
+	slices.Sort(names)
+	return fmt.Errorf("unknown branch %q (local branches: %s)", branch, strings.Join(names, ", "))
+}
@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"vkcli/internal/conversation"
+)
+
+// commandOutputLines is how many trailing lines of a command's output are
+// shown in conversation output.
+const commandOutputLines = 12
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiDim    = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// colorEnabled reports whether ANSI colors should be written to w: only
+// for terminals, and never when NO_COLOR is set.
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// painter wraps text in ANSI codes when enabled.
+type painter bool
+
+func (p painter) paint(code, text string) string {
+	if !p || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// renderToolUse prints a tool_use entry according to its action type.
+func renderToolUse(w io.Writer, entry conversation.Entry, p painter) {
+	action := entry.EntryType.ActionType
+	status := toolStatus(entry.EntryType.Status)
+	suffix := ""
+	if status == "failed" || status == "denied" {
+		suffix = " " + p.paint(ansiRed, "("+status+")")
+	}
+
+	if action == nil {
+		fmt.Fprintf(w, "── > %s%s\n", entry.Content, suffix)
+		return
+	}
+
+	switch action.Action {
+	case "file_read":
+		fmt.Fprintf(w, "── 📖 Read %s%s\n", p.paint(ansiBold, action.Path), suffix)
+	case "file_edit":
+		fmt.Fprintf(w, "── ✏️  Edit %s%s\n", p.paint(ansiBold, action.Path), suffix)
+		for _, change := range action.Changes {
+			renderFileChange(w, change, p)
+		}
+	case "command_run":
+		fmt.Fprintf(w, "── $ %s%s\n", p.paint(ansiBold, action.Command), suffix)
+		renderCommandResult(w, action.Result, p)
+	case "search":
+		line := fmt.Sprintf("── 🔍 Search %q", action.Query)
+		if hits, ok := searchHitCount(action.ToolResult); ok {
+			line += fmt.Sprintf(" (%d hits)", hits)
+		}
+		fmt.Fprintf(w, "%s%s\n", line, suffix)
+	case "web_fetch":
+		fmt.Fprintf(w, "── 🌐 Fetch %s%s\n", action.URL, suffix)
+	case "todo_management":
+		fmt.Fprintf(w, "── 📋 Todo list%s\n", suffix)
+		for _, todo := range action.Todos {
+			fmt.Fprintf(w, "   %s\n", formatTodo(todo, p))
+		}
+	case "plan_presentation":
+		fmt.Fprintf(w, "── 🗺  Plan%s\n", suffix)
+		writeIndented(w, action.Plan, "   ")
+	case "task_create":
+		fmt.Fprintf(w, "── 🧩 Subtask: %s%s\n", action.Description, suffix)
+	case "tool":
+		name := action.ToolName
+		if name == "" {
+			name = entry.EntryType.ToolName
+		}
+		line := fmt.Sprintf("── 🔧 %s", p.paint(ansiBold, name))
+		if args := compactJSON(action.Arguments, 120); args != "" {
+			line += " " + p.paint(ansiDim, args)
+		}
+		fmt.Fprintf(w, "%s%s\n", line, suffix)
+	default:
+		fmt.Fprintf(w, "── > %s%s\n", entry.Content, suffix)
+	}
+}
+
+func renderFileChange(w io.Writer, change conversation.FileChange, p painter) {
+	switch change.Action {
+	case "edit":
+		writeIndented(w, colorizeDiff(strings.TrimRight(change.UnifiedDiff, "\n"), p), "   ")
+	case "write":
+		lines := strings.Split(strings.TrimRight(change.Content, "\n"), "\n")
+		fmt.Fprintf(w, "   %s\n", p.paint(ansiDim, fmt.Sprintf("(write, %d lines)", len(lines))))
+		var diff strings.Builder
+		for _, l := range lines {
+			diff.WriteString("+" + l + "\n")
+		}
+		writeIndented(w, colorizeDiff(strings.TrimRight(diff.String(), "\n"), p), "   ")
+	case "delete":
+		fmt.Fprintf(w, "   %s\n", p.paint(ansiRed, "(deleted)"))
+	case "rename":
+		fmt.Fprintf(w, "   %s\n", p.paint(ansiYellow, "(renamed to "+change.NewPath+")"))
+	}
+}
+
+func renderCommandResult(w io.Writer, result *conversation.CommandResult, p painter) {
+	if result == nil {
+		return
+	}
+	if es := result.ExitStatus; es != nil {
+		switch {
+		case es.Code != nil && *es.Code == 0, es.Success != nil && *es.Success:
+			fmt.Fprintf(w, "   %s\n", p.paint(ansiGreen, exitStatusText(es)))
+		default:
+			fmt.Fprintf(w, "   %s\n", p.paint(ansiRed, exitStatusText(es)))
+		}
+	}
+	output := strings.TrimRight(result.Output, "\n")
+	if output == "" {
+		return
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > commandOutputLines {
+		fmt.Fprintf(w, "   %s\n", p.paint(ansiDim, fmt.Sprintf("… %d earlier lines", len(lines)-commandOutputLines)))
+		lines = lines[len(lines)-commandOutputLines:]
+	}
+	for _, l := range lines {
+		fmt.Fprintf(w, "   %s\n", p.paint(ansiDim, l))
+	}
+}
+
+func exitStatusText(es *conversation.ExitStatus) string {
+	if es.Code != nil {
+		return fmt.Sprintf("exit code %d", *es.Code)
+	}
+	if es.Success != nil && *es.Success {
+		return "succeeded"
+	}
+	return "failed"
+}
+
+func formatTodo(todo conversation.Todo, p painter) string {
+	switch strings.ToLower(todo.Status) {
+	case "completed", "done":
+		return p.paint(ansiGreen, "[x]") + " " + p.paint(ansiDim, todo.Content)
+	case "in_progress", "inprogress":
+		return p.paint(ansiYellow, "[~]") + " " + todo.Content
+	case "cancelled":
+		return p.paint(ansiDim, "[-] "+todo.Content)
+	}
+	return "[ ] " + todo.Content
+}
+
+// colorizeDiff colors the lines of a unified diff.
+func colorizeDiff(diff string, p painter) string {
+	if !p {
+		return diff
+	}
+	lines := strings.Split(diff, "\n")
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			lines[i] = p.paint(ansiBold, l)
+		case strings.HasPrefix(l, "+"):
+			lines[i] = p.paint(ansiGreen, l)
+		case strings.HasPrefix(l, "-"):
+			lines[i] = p.paint(ansiRed, l)
+		case strings.HasPrefix(l, "@@"):
+			lines[i] = p.paint(ansiCyan, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toolStatus extracts the status of a tool call, which the server sends
+// either as a string or as {"status": "..."}.
+func toolStatus(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.ToLower(s)
+	}
+	var obj struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return strings.ToLower(obj.Status)
+	}
+	return ""
+}
+
+// searchHitCount derives the number of hits from a search result when the
+// executor reports one: an array of matches or newline-separated text.
+func searchHitCount(raw json.RawMessage) (int, bool) {
+	if len(raw) == 0 {
+		return 0, false
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return len(list), true
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return 0, true
+		}
+		return len(strings.Split(text, "\n")), true
+	}
+	return 0, false
+}
+
+func compactJSON(raw json.RawMessage, limit int) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	compact, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	s := string(compact)
+	if len([]rune(s)) > limit {
+		s = string([]rune(s)[:limit]) + "…"
+	}
+	return s
+}
+
+func writeIndented(w io.Writer, text, indent string) {
+	if text == "" {
+		return
+	}
+	for _, l := range strings.Split(text, "\n") {
+		fmt.Fprintf(w, "%s%s\n", indent, l)
+	}
+}
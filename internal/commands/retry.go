@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"vkcli/internal/api"
+	"vkcli/internal/conversation"
+)
+
+// retryErrorMarker starts the block that --retry-with-error adds to a task
+// description while a retry is started.
+const retryErrorMarker = "<!-- vkcli: previous attempt error -->"
+
+// maxRetryErrorLen caps the error text added to a description.
+const maxRetryErrorLen = 2000
+
+// retryPolicy says how often and how a failed attempt is started again.
+type retryPolicy struct {
+	Retries   int
+	Executor  string
+	WithError bool
+}
+
+// parseFlag consumes args[*i] if it is a retry flag and reports whether it
+// did.
+func (p *retryPolicy) parseFlag(args []string, i *int) (bool, error) {
+	if v, ok, err := takeFlagValue(args, i, "--retries"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return true, fmt.Errorf("invalid --retries %q", v)
+		}
+		p.Retries = n
+		return true, nil
+	}
+	if v, ok, err := takeFlagValue(args, i, "--retry-executor"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		p.Executor = strings.TrimSpace(v)
+		return true, nil
+	}
+	if args[*i] == "--retry-with-error" {
+		p.WithError = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// shouldRetry reports whether an attempt that ended with outcome is retried
+// after tries attempts in total.
+func (p retryPolicy) shouldRetry(outcome string, tries int) bool {
+	return outcome == "agent_error" && tries <= p.Retries
+}
+
+// resolve normalizes and validates the retry executor.
+func (p *retryPolicy) resolve() error {
+	if p.Executor == "" {
+		return nil
+	}
+	p.Executor = strings.ToUpper(p.Executor)
+	return validateExecutor(p.Executor, "")
+}
+
+// settings returns the settings for a retry: those of the first attempt,
+// with the retry executor and its default variant if one is set.
+func (p retryPolicy) settings(first execSettings) execSettings {
+	if p.Executor != "" && p.Executor != first.Executor {
+		first.Executor = p.Executor
+		first.Variant = ""
+	}
+	return first
+}
+
+// startRetry starts the next attempt of a task after failedAttemptID
+// failed. With WithError the last error of the failed attempt is added to
+// the task description only while the attempt is created, since the server
+// builds the agent's prompt from the description at that moment; the
+// original description is restored right after. A failure to add or
+// restore the error is returned as warning and does not stop the retry.
+func (p retryPolicy) startRetry(taskID, failedAttemptID string, settings execSettings) (attemptID string, warning, err error) {
+	message := ""
+	if p.WithError {
+		message = lastAttemptError(failedAttemptID)
+	}
+	if message == "" {
+		attemptID, err = startAttempt(taskID, settings)
+		return attemptID, nil, err
+	}
+
+	client := apiClient()
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return "", nil, err
+	}
+	original := task.Description
+	if err := setTaskDescription(taskID, appendErrorContext(original, failedAttemptID, message)); err != nil {
+		warning = fmt.Errorf("could not add the error to the task: %w", err)
+		attemptID, err = startAttempt(taskID, settings)
+		return attemptID, warning, err
+	}
+
+	attemptID, err = startAttempt(taskID, settings)
+	if restoreErr := setTaskDescription(taskID, original); restoreErr != nil {
+		warning = fmt.Errorf("could not restore the task description: %w", restoreErr)
+	}
+	return attemptID, warning, err
+}
+
+// setTaskDescription replaces the description of a task, keeping its
+// current title and status.
+func setTaskDescription(taskID, description string) error {
+	client := apiClient()
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	_, err = client.UpdateTask(taskID, api.UpdateTaskRequest{
+		Title:       task.Title,
+		Description: description,
+		Status:      task.Status,
+	})
+	return err
+}
+
+// appendErrorContext adds a block describing the failure of attemptID to a
+// description, replacing the block of an earlier retry if one was left.
+func appendErrorContext(description, attemptID, message string) string {
+	if i := strings.Index(description, retryErrorMarker); i >= 0 {
+		description = description[:i]
+	}
+	if len(message) > maxRetryErrorLen {
+		// Cut on a rune boundary so the description stays valid UTF-8.
+		n := maxRetryErrorLen
+		for n > 0 && !utf8.RuneStart(message[n]) {
+			n--
+		}
+		message = message[:n] + "…"
+	}
+
+	var b strings.Builder
+	if description = strings.TrimRight(description, "\n"); description != "" {
+		b.WriteString(description)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(retryErrorMarker)
+	fmt.Fprintf(&b, "\nThe previous attempt (%s) failed with:\n\n```\n%s\n```\n", attemptID, message)
+	return b.String()
+}
+
+// lastAttemptError returns the last error message of the latest coding
+// agent process of an attempt, or a note about its exit code.
+func lastAttemptError(attemptID string) string {
+	processes, err := apiClient().ListExecutionProcesses(attemptID)
+	if err != nil {
+		return ""
+	}
+
+	for i := len(processes) - 1; i >= 0; i-- {
+		p := processes[i]
+		if p.RunReason != "codingagent" {
+			continue
+		}
+		if entries, err := fetchNormalizedLogs(p.ID); err == nil {
+			for j := len(entries) - 1; j >= 0; j-- {
+				if entries[j].Type() == conversation.ErrorMessage {
+					return strings.TrimSpace(entries[j].Content)
+				}
+			}
+		}
+		if p.ExitCode != nil {
+			return fmt.Sprintf("the coding agent exited with code %d", *p.ExitCode)
+		}
+		return fmt.Sprintf("the coding agent ended with status %s", p.Status)
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAppendErrorContextCutsOnRuneBoundary(t *testing.T) {
+	// "あ" is three bytes, so maxRetryErrorLen falls inside a character.
+	message := "x" + strings.Repeat("あ", maxRetryErrorLen)
+	got := appendErrorContext("Fix it.", "a1", message)
+	if !utf8.ValidString(got) {
+		t.Fatalf("description is not valid UTF-8: %q", got[len(got)-40:])
+	}
+	if !strings.Contains(got, "あ…\n```") {
+		t.Errorf("error text is not cut before the marker: %q", got[len(got)-40:])
+	}
+	if n := strings.Count(got, "あ"); n != (maxRetryErrorLen-1)/3 {
+		t.Errorf("kept %d characters, want %d", n, (maxRetryErrorLen-1)/3)
+	}
+}
+
+func TestAppendErrorContextReplacesEarlierBlock(t *testing.T) {
+	first := appendErrorContext("Fix it.\n", "a1", "boom")
+	got := appendErrorContext(first, "a2", "bang")
+	if strings.Count(got, retryErrorMarker) != 1 || strings.Contains(got, "boom") {
+		t.Errorf("earlier error block kept: %q", got)
+	}
+	if !strings.HasPrefix(got, "Fix it.\n\n"+retryErrorMarker) || !strings.Contains(got, "(a2) failed with:\n\n```\nbang\n```") {
+		t.Errorf("unexpected description: %q", got)
+	}
+}
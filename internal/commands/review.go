@@ -0,0 +1,390 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/state"
+)
+
+const reviewUsage = "vkcli review <project_id> [--status inreview] [--title-match <regex>] " +
+	"[--updated-since <t>] [--sort created|updated|title] [--limit N] [--reverse]"
+
+// reviewKeys is the action prompt of vkcli review.
+const reviewKeys = "[m]erge  [f]ollow-up  [r]e-run  [d]one  [v]iew diff  [s]kip  [q]uit > "
+
+type ReviewCommand struct{}
+
+func NewReviewCommand() Command {
+	return &ReviewCommand{}
+}
+
+func (c *ReviewCommand) Name() string {
+	return "review"
+}
+
+func (c *ReviewCommand) Usage() string {
+	return reviewUsage
+}
+
+func (c *ReviewCommand) Description() string {
+	return "INREVIEW のタスクを順にレビュー"
+}
+
+// reviewRecord is one decision of a review session, as written to the
+// session log.
+type reviewRecord struct {
+	Time      time.Time `json:"time"`
+	ProjectID string    `json:"project_id"`
+	TaskID    string    `json:"task_id"`
+	Title     string    `json:"title"`
+	AttemptID string    `json:"attempt_id,omitempty"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// reviewSession walks the tasks of one vkcli review run.
+type reviewSession struct {
+	projectID string
+	client    *api.Client
+	log       *state.SessionLog
+	counts    map[string]int
+	logged    int
+}
+
+func (c *ReviewCommand) Run(args []string) error {
+	var projectID string
+	filter := taskFilter{Sort: "updated"}
+	for i := 0; i < len(args); i++ {
+		if ok, err := filter.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		switch arg := args[i]; {
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if projectID != "" {
+				return fmt.Errorf("Usage: %s", reviewUsage)
+			}
+			projectID = strings.TrimSpace(arg)
+		}
+	}
+	if projectID == "" {
+		return fmt.Errorf("Usage: %s", reviewUsage)
+	}
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []string{"INREVIEW"}
+	}
+
+	client := apiClient()
+	all, err := client.ListTasks(projectID)
+	if err != nil {
+		return err
+	}
+	tasks := filter.apply(all)
+	if len(tasks) == 0 {
+		fmt.Println("No tasks to review.")
+		return nil
+	}
+
+	s := &reviewSession{
+		projectID: projectID,
+		client:    client,
+		log:       state.NewSessionLog("reviews", time.Now()),
+		counts:    map[string]int{},
+	}
+	for i, task := range tasks {
+		quit, err := s.review(task, i+1, len(tasks))
+		if err != nil {
+			s.printSummary()
+			return err
+		}
+		if quit {
+			break
+		}
+	}
+	s.printSummary()
+	return nil
+}
+
+// review shows one task and asks for actions until one of them decides it.
+// It reports whether the user quit the session.
+func (s *reviewSession) review(task api.Task, n, total int) (bool, error) {
+	fmt.Printf("\n%s\n", sectionDivider(fmt.Sprintf("[%d/%d] %s", n, total, task.Title)))
+	fmt.Printf("Task:        %s (%s)\n", task.ID, normalizeStatusString(task.Status))
+
+	attempts, err := s.client.ListAttempts(task.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(attempts) == 0 {
+		fmt.Println("No attempts; skipped.")
+		return false, s.record(task, nil, "skipped", "no attempts")
+	}
+	attempt := &attempts[len(attempts)-1]
+	showReviewAttempt(s.client, attempt)
+
+	for {
+		key, err := readKey(reviewKeys)
+		if err != nil {
+			return false, err
+		}
+
+		var action, detail string
+		switch key {
+		case 'm':
+			err = mergeAttempt(s.client, attempt, true)
+			action = "merged"
+		case 'f':
+			action, detail, err = s.followUp(attempt)
+		case 'r':
+			action, detail, err = s.rerun(task, attempt)
+		case 'd':
+			err = markTaskDone(s.client, task.ID)
+			if err == nil {
+				fmt.Printf("Task %s is now DONE.\n", task.ID)
+			}
+			action = "done"
+		case 'v':
+			err = viewReviewDiff(attempt)
+		case 's', '\n':
+			action = "skipped"
+		case 'q':
+			return true, nil
+		default:
+			fmt.Println("Unknown key.")
+			continue
+		}
+
+		if err != nil {
+			if ExitCode(err) == ExitUnreachable {
+				return false, err
+			}
+			// Outcomes such as merge conflicts were already reported.
+			if exitErr := (*ExitError)(nil); !errors.As(err, &exitErr) || exitErr.Err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			continue
+		}
+		if action == "" {
+			continue
+		}
+		return false, s.record(task, attempt, action, detail)
+	}
+}
+
+// showReviewAttempt prints what a reviewer needs to decide on an attempt:
+// its summary, its diffstat and the agent's final message.
+func showReviewAttempt(client *api.Client, attempt *api.TaskAttempt) {
+	if summary, err := summarizeAttempt(client, *attempt); err == nil {
+		fmt.Printf("Attempt:     %s (%s, %s, %s)\n", summary.ID, summary.Executor, summary.Status, summary.Duration)
+	}
+	fmt.Printf("Branch:      %s -> %s\n", attempt.Branch, attempt.BaseBranch)
+
+	fmt.Printf("\n%s\n", sectionDivider("Changes"))
+	stat, err := attemptDiff(attempt, diffStat, false)
+	switch {
+	case err != nil:
+		fmt.Printf("(diff unavailable: %v)\n", err)
+	case strings.TrimSpace(stat) == "":
+		fmt.Println("(no changes)")
+	default:
+		fmt.Print(stat)
+	}
+
+	fmt.Printf("\n%s\n", sectionDivider("Final message"))
+	message, err := finalAssistantMessage(attempt.ID)
+	switch {
+	case err != nil:
+		fmt.Printf("(unavailable: %v)\n", err)
+	case message == "":
+		fmt.Println("(none)")
+	default:
+		fmt.Println(message)
+	}
+	fmt.Println()
+}
+
+func viewReviewDiff(attempt *api.TaskAttempt) error {
+	text, err := attemptDiff(attempt, diffPatch, false)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		fmt.Println("No changes.")
+		return nil
+	}
+	return writeDiff(text, diffOptions{})
+}
+
+// followUp asks for a prompt and sends it to the attempt without waiting
+// for the agent.
+func (s *reviewSession) followUp(attempt *api.TaskAttempt) (action, detail string, err error) {
+	prompt, err := promptLine("Follow-up prompt (empty opens $EDITOR): ")
+	if err != nil {
+		return "", "", err
+	}
+	if prompt == "" {
+		if prompt, err = editFollowupPrompt(); err != nil {
+			return "", "", err
+		}
+		prompt = strings.TrimSpace(prompt)
+	}
+	if prompt == "" {
+		fmt.Println("Empty prompt, nothing sent.")
+		return "", "", nil
+	}
+
+	process, err := s.client.FollowUp(attempt.ID, api.FollowUpRequest{Prompt: prompt})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to send follow-up: %w", err)
+	}
+	fmt.Printf("Sent follow-up: process %s\n", process.ID)
+	return "followup", prompt, nil
+}
+
+// rerun starts a new attempt of the task with an executor chosen by the
+// user, on the base branch of the reviewed attempt.
+func (s *reviewSession) rerun(task api.Task, attempt *api.TaskAttempt) (action, detail string, err error) {
+	question := "Executor"
+	if info, err := s.client.GetSystemInfo(); err == nil && len(info.Executors) > 0 {
+		question += fmt.Sprintf(" (%s)", strings.Join(sortedKeys(info.Executors), ", "))
+	}
+	executor, err := promptLine(question + ", empty to cancel: ")
+	if err != nil || executor == "" {
+		return "", "", err
+	}
+
+	settings := execSettings{Executor: executor, BaseBranch: attempt.BaseBranch}
+	if err := settings.resolve(task.ProjectID); err != nil {
+		return "", "", err
+	}
+	attemptID, err := startAttempt(task.ID, settings)
+	if err != nil {
+		return "", "", err
+	}
+	fmt.Printf("Started attempt: %s (%s)\n", attemptID, settings)
+	return "rerun", fmt.Sprintf("%s attempt=%s", settings, attemptID), nil
+}
+
+func (s *reviewSession) record(task api.Task, attempt *api.TaskAttempt, action, detail string) error {
+	s.counts[action]++
+	rec := reviewRecord{
+		Time:      time.Now(),
+		ProjectID: s.projectID,
+		TaskID:    task.ID,
+		Title:     task.Title,
+		Action:    action,
+		Detail:    detail,
+	}
+	if attempt != nil {
+		rec.AttemptID = attempt.ID
+	}
+	if err := s.log.Append(rec); err != nil {
+		return fmt.Errorf("writing review log: %w", err)
+	}
+	s.logged++
+	return nil
+}
+
+func (s *reviewSession) printSummary() {
+	if s.logged == 0 {
+		return
+	}
+	var parts []string
+	for _, action := range []string{"merged", "followup", "rerun", "done", "skipped"} {
+		if n := s.counts[action]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", action, n))
+		}
+	}
+	fmt.Printf("\nReviewed %d %s: %s\n", s.logged, plural(s.logged, "task", "tasks"), strings.Join(parts, " "))
+	fmt.Printf("Session log: %s\n", s.log.Path())
+}
+
+// readKey prints prompt on stderr and reads a single key press. On a
+// terminal, line buffering is switched off with stty for the read, and
+// Ctrl-C or Ctrl-D read as "q"; otherwise the first character of a line is
+// used, an empty line reads as "\n" and the end of the input as "q".
+func readKey(prompt string) (byte, error) {
+	if !isTerminal(os.Stdin) {
+		return readKeyLine(prompt)
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return readKeyLine(prompt)
+	}
+	defer stty(strings.TrimSpace(saved))
+
+	fmt.Fprint(os.Stderr, prompt)
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return 0, err
+	}
+	key, err := stdinReader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	drainKeys()
+
+	switch key {
+	case 3, 4:
+		key = 'q'
+	case '\r':
+		key = '\n'
+	}
+	if key >= 'A' && key <= 'Z' {
+		key += 'a' - 'A'
+	}
+	if key > ' ' && key < 0x7f {
+		fmt.Fprintf(os.Stderr, "%c", key)
+	}
+	fmt.Fprintln(os.Stderr)
+	return key, nil
+}
+
+// readKeyLine is readKey for input that is not a terminal.
+func readKeyLine(prompt string) (byte, error) {
+	line, err := promptLine(prompt)
+	switch {
+	case errors.Is(err, io.EOF):
+		fmt.Fprintln(os.Stderr)
+		return 'q', nil
+	case err != nil:
+		return 0, err
+	case line == "":
+		return '\n', nil
+	}
+	return strings.ToLower(line)[0], nil
+}
+
+// drainKeys discards input that is already waiting after a key press, such
+// as the rest of an arrow key's escape sequence, so that it is not read as
+// further keys. The terminal must be in non-canonical mode.
+func drainKeys() {
+	stdinReader.Discard(stdinReader.Buffered())
+	if _, err := stty("min", "0", "time", "0"); err != nil {
+		return
+	}
+	buf := make([]byte, 64)
+	for {
+		if n, err := os.Stdin.Read(buf); n == 0 || err != nil {
+			return
+		}
+	}
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,625 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+	"vkcli/internal/state"
+)
+
+const runQueueUsage = "vkcli run-queue <project_id> [--status todo] [--concurrency N] [--stop-on-error] " +
+	"[--order created|title] [--executor <name>] [--variant <name>] [--base-branch <branch>] [--timeout <duration>] [--report <file>] " +
+	"[--retries N] [--retry-executor <name>] [--retry-with-error]"
+
+type RunQueueCommand struct{}
+
+func NewRunQueueCommand() Command {
+	return &RunQueueCommand{}
+}
+
+func (c *RunQueueCommand) Name() string {
+	return "run-queue"
+}
+
+func (c *RunQueueCommand) Usage() string {
+	return runQueueUsage
+}
+
+func (c *RunQueueCommand) Description() string {
+	return "複数タスクを順次または並列に実行"
+}
+
+// runQueueOptions are the parsed arguments of the run-queue command.
+type runQueueOptions struct {
+	ProjectID   string
+	Filter      taskFilter
+	Concurrency int
+	StopOnError bool
+	Settings    execSettings
+	Timeout     time.Duration
+	Report      string
+	Retry       retryPolicy
+}
+
+// Job states besides the attempt outcomes (success, agent_error, timeout,
+// cancelled) reported by attemptOutcome.
+const (
+	jobPending    = "pending"
+	jobRunning    = "running"
+	jobSkipped    = "skipped"
+	jobStartError = "start_error"
+)
+
+// queueJob is one task of the queue and, once started, its attempt.
+type queueJob struct {
+	TaskID     string    `json:"task_id"`
+	Title      string    `json:"title"`
+	State      string    `json:"state"`
+	Status     string    `json:"status,omitempty"`
+	AttemptID  string    `json:"attempt_id,omitempty"`
+	Attempts   []string  `json:"attempts,omitempty"`
+	DependsOn  []string  `json:"depends_on,omitempty"`
+	StartedAt  time.Time `json:"started_at,omitzero"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func (j *queueJob) elapsed(now time.Time) time.Duration {
+	switch {
+	case j.StartedAt.IsZero():
+		return 0
+	case j.FinishedAt.IsZero():
+		return now.Sub(j.StartedAt).Round(time.Second)
+	}
+	return j.FinishedAt.Sub(j.StartedAt).Round(time.Second)
+}
+
+// queueReport is written by --report.
+type queueReport struct {
+	ProjectID  string      `json:"project_id"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt time.Time   `json:"finished_at"`
+	Counts     []jobCount  `json:"counts"`
+	Jobs       []*queueJob `json:"jobs"`
+}
+
+type jobCount struct {
+	State string `json:"state"`
+	Count int    `json:"count"`
+}
+
+func (c *RunQueueCommand) Run(args []string) error {
+	opts, err := parseRunQueueArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.Settings.resolve(opts.ProjectID); err != nil {
+		return err
+	}
+	if err := opts.Retry.resolve(); err != nil {
+		return err
+	}
+
+	client := apiClient()
+	all, err := client.ListTasks(opts.ProjectID)
+	if err != nil {
+		return err
+	}
+	tasks := opts.Filter.apply(all)
+	if len(tasks) == 0 {
+		fmt.Println("No tasks to run.")
+		return nil
+	}
+
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		return err
+	}
+	r := &queueRunner{
+		opts:     opts,
+		client:   client,
+		table:    newProgressTable(os.Stdout),
+		external: map[string]string{},
+	}
+	if err := r.plan(tasks, all, st); err != nil {
+		return err
+	}
+
+	ctx, cancel := monitorContext(0)
+	defer cancel()
+
+	startedAt := time.Now()
+	runErr := r.run(ctx)
+	for _, job := range r.jobs {
+		if job.State == jobPending {
+			job.State = jobSkipped
+		}
+	}
+	r.table.render(r.jobs, time.Now())
+
+	report := queueReport{
+		ProjectID:  opts.ProjectID,
+		StartedAt:  startedAt.UTC(),
+		FinishedAt: time.Now().UTC(),
+		Counts:     countJobs(r.jobs),
+		Jobs:       r.jobs,
+	}
+	printQueueSummary(os.Stdout, report)
+	if opts.Report != "" {
+		if err := writeQueueReport(opts.Report, report); err != nil {
+			return err
+		}
+		fmt.Printf("Report written to %s\n", opts.Report)
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	return queueExitError(r.jobs)
+}
+
+func parseRunQueueArgs(args []string) (runQueueOptions, error) {
+	opts := runQueueOptions{Concurrency: 1}
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--order"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			if v != "created" && v != "title" {
+				return opts, fmt.Errorf("invalid --order %q (expected created or title)", v)
+			}
+			opts.Filter.Sort = v
+			continue
+		}
+		if ok, err := opts.Filter.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if ok, err := opts.Retry.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--concurrency"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return opts, fmt.Errorf("invalid --concurrency %q", v)
+			}
+			opts.Concurrency = n
+			continue
+		}
+		if ok, err := opts.Settings.parseFlag(args, &i); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--timeout"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			timeout, err := time.ParseDuration(v)
+			if err != nil || timeout <= 0 {
+				return opts, fmt.Errorf("invalid --timeout %q: use a duration such as 45m or 2h", v)
+			}
+			opts.Timeout = timeout
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--report"); ok || err != nil {
+			if err != nil {
+				return opts, err
+			}
+			opts.Report = v
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--stop-on-error":
+			opts.StopOnError = true
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("unknown flag: %s", arg)
+		case opts.ProjectID != "":
+			return opts, fmt.Errorf("multiple project IDs specified")
+		default:
+			opts.ProjectID = strings.TrimSpace(arg)
+		}
+	}
+
+	if opts.ProjectID == "" {
+		return opts, fmt.Errorf("Usage: %s", runQueueUsage)
+	}
+	if len(opts.Filter.Statuses) == 0 {
+		opts.Filter.Statuses = []string{"TODO"}
+	}
+	return opts, nil
+}
+
+// queueRunner starts the jobs of a queue, at most Concurrency at a time,
+// and follows all running attempts with a single task list poll per
+// interval.
+type queueRunner struct {
+	opts        runQueueOptions
+	client      *api.Client
+	jobs        []*queueJob
+	table       *progressTable
+	stopping    bool
+	unreachable int
+
+	// byID indexes jobs by task ID.
+	byID map[string]*queueJob
+	// external holds the status of prerequisites outside the queue.
+	external map[string]string
+}
+
+// plan creates the jobs for tasks in dependency order. all is every task of
+// the project and provides the status of prerequisites outside the queue.
+func (r *queueRunner) plan(tasks, all []api.Task, st *state.State) error {
+	byTask := make(map[string]api.Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	deps := make(map[string][]string, len(tasks))
+	for _, t := range tasks {
+		byTask[t.ID] = t
+		ids = append(ids, t.ID)
+		deps[t.ID] = taskDependencies(st, t)
+	}
+
+	ordered, err := orderByDependencies(ids, deps)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]string, len(all))
+	for _, t := range all {
+		statuses[t.ID] = normalizeStatusString(t.Status)
+	}
+
+	r.byID = make(map[string]*queueJob, len(ordered))
+	for _, id := range ordered {
+		t := byTask[id]
+		job := &queueJob{TaskID: t.ID, Title: t.Title, State: jobPending, DependsOn: deps[id]}
+		r.jobs = append(r.jobs, job)
+		r.byID[id] = job
+	}
+	for _, job := range r.jobs {
+		for _, dep := range job.DependsOn {
+			if _, ok := r.byID[dep]; ok {
+				continue
+			}
+			status, ok := statuses[dep]
+			if !ok {
+				status = getTaskStatus(dep)
+			}
+			r.external[dep] = status
+		}
+	}
+	return nil
+}
+
+// dependenciesReady reports whether every prerequisite of job succeeded.
+// reason is set when a prerequisite can no longer succeed, so job must be
+// skipped.
+func (r *queueRunner) dependenciesReady(job *queueJob) (ready bool, reason string) {
+	ready = true
+	for _, dep := range job.DependsOn {
+		if prereq, ok := r.byID[dep]; ok {
+			switch prereq.State {
+			case "success":
+			case jobPending, jobRunning:
+				ready = false
+			default:
+				return false, fmt.Sprintf("prerequisite %s ended in %s", dep, prereq.State)
+			}
+			continue
+		}
+		switch status := r.external[dep]; status {
+		case "INREVIEW", "DONE":
+		default:
+			return false, fmt.Sprintf("prerequisite %s is %s", dep, status)
+		}
+	}
+	return ready, ""
+}
+
+func (r *queueRunner) run(ctx context.Context) error {
+	for {
+		r.startPending()
+		r.table.render(r.jobs, time.Now())
+		if r.count(jobRunning) == 0 && (r.stopping || r.count(jobPending) == 0) {
+			return nil
+		}
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return r.abort()
+		}
+		if err := r.poll(); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *queueRunner) count(state string) int {
+	n := 0
+	for _, job := range r.jobs {
+		if job.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *queueRunner) startPending() {
+	running := r.count(jobRunning)
+	for _, job := range r.jobs {
+		if r.stopping || running >= r.opts.Concurrency {
+			return
+		}
+		if job.State != jobPending {
+			continue
+		}
+		ready, reason := r.dependenciesReady(job)
+		if reason != "" {
+			job.State = jobSkipped
+			job.Error = reason
+			continue
+		}
+		if !ready {
+			continue
+		}
+
+		job.StartedAt = time.Now()
+		attemptID, err := startAttempt(job.TaskID, r.opts.Settings)
+		if err != nil {
+			job.Error = err.Error()
+			r.finish(job, jobStartError)
+			continue
+		}
+		job.AttemptID = attemptID
+		job.Attempts = append(job.Attempts, attemptID)
+		job.State = jobRunning
+		running++
+	}
+}
+
+// poll refreshes the task statuses of the project once and finishes the
+// running jobs whose task reached a final status or whose time is up.
+func (r *queueRunner) poll() error {
+	tasks, err := r.client.ListTasks(r.opts.ProjectID)
+	if err != nil {
+		if !errors.Is(err, api.ErrUnreachable) {
+			return err
+		}
+		r.unreachable++
+		if r.unreachable >= maxUnreachablePolls {
+			return &ExitError{Code: ExitUnreachable, Err: err}
+		}
+		return nil
+	}
+	r.unreachable = 0
+
+	statuses := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		statuses[t.ID] = normalizeStatusString(t.Status)
+	}
+
+	now := time.Now()
+	for _, job := range r.jobs {
+		if job.State != jobRunning {
+			continue
+		}
+		job.Status = statuses[job.TaskID]
+		if attemptFinished(job.AttemptID, job.Status) {
+			outcome, _ := attemptOutcome(job.AttemptID, job.Status)
+			if r.opts.Retry.shouldRetry(outcome, len(job.Attempts)) && !r.stopping {
+				r.retry(job)
+				continue
+			}
+			r.finish(job, outcome)
+			continue
+		}
+		if r.opts.Timeout > 0 && now.Sub(job.StartedAt) > r.opts.Timeout {
+			if _, err := stopRunningProcesses(job.AttemptID); err != nil {
+				job.Error = err.Error()
+			}
+			r.finish(job, "timeout")
+		}
+	}
+	return nil
+}
+
+// retry starts a fresh attempt for a job whose attempt failed.
+func (r *queueRunner) retry(job *queueJob) {
+	attemptID, warning, err := r.opts.Retry.startRetry(job.TaskID, job.AttemptID, r.opts.Retry.settings(r.opts.Settings))
+	if warning != nil {
+		job.Error = warning.Error()
+	}
+	if err != nil {
+		job.Error = err.Error()
+		r.finish(job, jobStartError)
+		return
+	}
+	job.AttemptID = attemptID
+	job.Attempts = append(job.Attempts, attemptID)
+	job.Status = ""
+	// Each attempt gets the full --timeout, as with exec.
+	job.StartedAt = time.Now()
+}
+
+func (r *queueRunner) finish(job *queueJob, state string) {
+	job.State = state
+	job.FinishedAt = time.Now()
+	if state != "success" && r.opts.StopOnError {
+		r.stopping = true
+	}
+}
+
+// abort handles SIGINT/SIGTERM: no new attempts are started and the user
+// is asked whether the running ones should be stopped on the server.
+func (r *queueRunner) abort() error {
+	r.stopping = true
+	running := r.count(jobRunning)
+	if running == 0 {
+		return &ExitError{Code: ExitCancelled}
+	}
+
+	stop, err := confirm(fmt.Sprintf("\nStop the %d running agent(s) on the server?", running))
+	if err != nil {
+		stop = false
+	}
+	for _, job := range r.jobs {
+		if job.State != jobRunning {
+			continue
+		}
+		if stop {
+			if _, err := stopRunningProcesses(job.AttemptID); err != nil {
+				job.Error = err.Error()
+			}
+		}
+		r.finish(job, "cancelled")
+	}
+	return &ExitError{Code: ExitCancelled}
+}
+
+// queueExitError maps the worst job outcome to an exit code.
+func queueExitError(jobs []*queueJob) error {
+	code := ExitOK
+	for _, job := range jobs {
+		switch job.State {
+		case "agent_error", jobStartError:
+			code = ExitAgentError
+		case "timeout":
+			if code == ExitOK || code == ExitCancelled {
+				code = ExitTimeout
+			}
+		case "cancelled":
+			if code == ExitOK {
+				code = ExitCancelled
+			}
+		}
+	}
+	if code == ExitOK {
+		return nil
+	}
+	return &ExitError{Code: code}
+}
+
+func countJobs(jobs []*queueJob) []jobCount {
+	var counts []jobCount
+	index := map[string]int{}
+	for _, job := range jobs {
+		i, ok := index[job.State]
+		if !ok {
+			i = len(counts)
+			index[job.State] = i
+			counts = append(counts, jobCount{State: job.State})
+		}
+		counts[i].Count++
+	}
+	return counts
+}
+
+func printQueueSummary(w io.Writer, report queueReport) {
+	parts := make([]string, 0, len(report.Counts))
+	for _, c := range report.Counts {
+		parts = append(parts, fmt.Sprintf("%s=%d", c.State, c.Count))
+	}
+	duration := report.FinishedAt.Sub(report.StartedAt).Round(time.Second)
+	fmt.Fprintf(w, "\nQueue finished in %s: %s\n", duration, strings.Join(parts, " "))
+	for _, job := range report.Jobs {
+		if len(job.Attempts) > 1 {
+			fmt.Fprintf(w, "  %s: attempts %s\n", job.TaskID, strings.Join(job.Attempts, ", "))
+		}
+		if job.Error != "" {
+			fmt.Fprintf(w, "  %s: %s\n", job.TaskID, job.Error)
+		}
+	}
+}
+
+func writeQueueReport(path string, report queueReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// progressTable shows the state of every job. On a terminal it redraws
+// the table in place; otherwise it prints a line whenever a job changes.
+type progressTable struct {
+	w     io.Writer
+	live  bool
+	lines int
+	last  map[*queueJob]string
+}
+
+func newProgressTable(w io.Writer) *progressTable {
+	return &progressTable{w: w, live: colorEnabled(w), last: map[*queueJob]string{}}
+}
+
+func (t *progressTable) render(jobs []*queueJob, now time.Time) {
+	if !t.live {
+		for _, job := range jobs {
+			key := jobState(job) + "/" + job.Status
+			if t.last[job] == key {
+				continue
+			}
+			t.last[job] = key
+			fmt.Fprintf(t.w, "%s  %s  %s  %s\n", now.Format("15:04:05"), job.TaskID, truncateText(job.Title, 40), jobLabel(job))
+		}
+		return
+	}
+
+	if t.lines > 0 {
+		fmt.Fprintf(t.w, "\x1b[%dA", t.lines)
+	}
+	fmt.Fprintf(t.w, "\x1b[2K%-38s  %-40s  %-12s  %-10s  %8s\n", "TASK ID", "TITLE", "STATE", "STATUS", "ELAPSED")
+	for _, job := range jobs {
+		elapsed := "-"
+		if d := job.elapsed(now); d > 0 {
+			elapsed = d.String()
+		}
+		status := job.Status
+		if status == "" {
+			status = "-"
+		}
+		fmt.Fprintf(t.w, "\x1b[2K%-38s  %-40s  %-12s  %-10s  %8s\n",
+			job.TaskID, truncateText(job.Title, 40), jobState(job), status, elapsed)
+	}
+	t.lines = len(jobs) + 1
+}
+
+// jobState is the state of a job, numbered from the second attempt on.
+func jobState(job *queueJob) string {
+	if len(job.Attempts) > 1 {
+		return fmt.Sprintf("%s #%d", job.State, len(job.Attempts))
+	}
+	return job.State
+}
+
+func jobLabel(job *queueJob) string {
+	if job.Status == "" {
+		return jobState(job)
+	}
+	return fmt.Sprintf("%s (%s)", jobState(job), job.Status)
+}
+
+// truncateText shortens s to at most n runes, marking the cut with "…".
+func truncateText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -1,15 +1,16 @@
 package commands
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/gorilla/websocket"
+	"vkcli/internal/api"
+	"vkcli/internal/conversation"
 )
 
 type ShowCommand struct{}
@@ -22,8 +23,10 @@ func (c *ShowCommand) Name() string {
 	return "show"
 }
 
+const showUsage = "vkcli show <task_id> [--with-messages] [--output <fmt>] | vkcli show --attempt <attempt_id>"
+
 func (c *ShowCommand) Usage() string {
-	return "vkcli show <task_id> [--with-messages]"
+	return showUsage
 }
 
 func (c *ShowCommand) Description() string {
@@ -31,38 +34,77 @@ func (c *ShowCommand) Description() string {
 }
 
 func (c *ShowCommand) Run(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("Usage: vkcli show <task_id> [--with-messages]")
-	}
-	id := args[0]
-	withMessages := len(args) >= 2 && args[1] == "--with-messages"
-
-	resp, err := http.Get(fmt.Sprintf("%s/tasks/%s", baseURL, id))
+	args, format, err := parseOutputFlags(args)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	var taskWrap struct {
-		Success bool                   `json:"success"`
-		Data    map[string]interface{} `json:"data"`
+	var id, attemptID string
+	withMessages := false
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--attempt"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			attemptID = strings.TrimSpace(v)
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--with-messages":
+			withMessages = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case id != "":
+			return fmt.Errorf("Usage: %s", showUsage)
+		default:
+			id = arg
+		}
+	}
+
+	client := apiClient()
+	if attemptID != "" {
+		// The conversation of a specific attempt is shown along with its
+		// task.
+		attempt, err := client.GetAttempt(attemptID)
+		if err != nil {
+			return err
+		}
+		if id != "" && id != attempt.TaskID {
+			return fmt.Errorf("attempt %s belongs to task %s, not %s", attempt.ID, attempt.TaskID, id)
+		}
+		id = attempt.TaskID
+		withMessages = true
+	}
+	if id == "" {
+		return fmt.Errorf("Usage: %s", showUsage)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&taskWrap); err != nil {
+	if withMessages && !format.IsTable() {
+		return fmt.Errorf("--with-messages and --attempt are only supported with table output")
+	}
+
+	task, err := client.GetTask(id)
+	if err != nil {
 		return err
 	}
+	if !format.IsTable() {
+		return writeOutput(format, task, taskColumns([]api.Task{*task}))
+	}
 
-	task := taskWrap.Data
-	fmt.Printf("ID:          %s\n", task["id"])
-	fmt.Printf("Title:       %s\n", task["title"])
-	fmt.Printf("Status:      %s\n", task["status"])
-	fmt.Printf("Created At:  %s\n", task["created_at"])
-	fmt.Printf("Updated At:  %s\n", task["updated_at"])
+	fmt.Printf("ID:          %s\n", task.ID)
+	fmt.Printf("Title:       %s\n", task.Title)
+	fmt.Printf("Status:      %s\n", task.Status)
+	fmt.Printf("Created At:  %s\n", formatTime(task.CreatedAt))
+	fmt.Printf("Updated At:  %s\n", formatTime(task.UpdatedAt))
 	fmt.Println()
 	fmt.Println("Description:")
-	fmt.Println(task["description"])
+	fmt.Println(task.Description)
 
 	if withMessages {
 		fmt.Printf("\n%s\n", sectionDivider("Messages"))
+		if attemptID != "" {
+			fmt.Printf("Attempt ID: %s\n\n", attemptID)
+			return showAttemptMessages(attemptID)
+		}
 		if err := showTaskWithMessages(id); err != nil {
 			return err
 		}
@@ -70,61 +112,35 @@ func (c *ShowCommand) Run(args []string) error {
 	return nil
 }
 
-type executionProcess struct {
-	ID             string `json:"id"`
-	ExecutorAction struct {
-		Typ struct {
-			Type   string `json:"type"`
-			Prompt string `json:"prompt"`
-		} `json:"typ"`
-	} `json:"executor_action"`
-}
-
 func showTaskWithMessages(taskID string) error {
-	attemptResp, err := http.Get(baseURL + "/task-attempts?task_id=" + taskID)
+	client := apiClient()
+	attempts, err := client.ListAttempts(taskID)
 	if err != nil {
 		return err
 	}
-	defer attemptResp.Body.Close()
-
-	var attemptWrapper struct {
-		Success bool                     `json:"success"`
-		Data    []map[string]interface{} `json:"data"`
-	}
-	if err := json.NewDecoder(attemptResp.Body).Decode(&attemptWrapper); err != nil {
-		return err
-	}
-	if len(attemptWrapper.Data) == 0 {
-		fmt.Println("No attempts found.")
-		return nil
-	}
-	latestAttempt, _ := attemptWrapper.Data[len(attemptWrapper.Data)-1]["id"].(string)
-	if latestAttempt == "" {
+	if len(attempts) == 0 || attempts[len(attempts)-1].ID == "" {
 		fmt.Println("No attempts found.")
 		return nil
 	}
+	latestAttempt := attempts[len(attempts)-1].ID
 	fmt.Printf("Latest Attempt ID: %s\n\n", latestAttempt)
+	return showAttemptMessages(latestAttempt)
+}
 
-	execResp, err := http.Get(baseURL + "/execution-processes?task_attempt_id=" + latestAttempt)
+// showAttemptMessages prints the conversation of every execution process
+// of an attempt.
+func showAttemptMessages(attemptID string) error {
+	processes, err := apiClient().ListExecutionProcesses(attemptID)
 	if err != nil {
 		return err
 	}
-	defer execResp.Body.Close()
 
-	var execWrapper struct {
-		Success bool               `json:"success"`
-		Data    []executionProcess `json:"data"`
-	}
-	if err := json.NewDecoder(execResp.Body).Decode(&execWrapper); err != nil {
-		return err
-	}
-
-	if len(execWrapper.Data) == 0 {
+	if len(processes) == 0 {
 		fmt.Println("(no execution processes found)")
 		return nil
 	}
 
-	for _, exec := range execWrapper.Data {
+	for _, exec := range processes {
 		fmt.Printf("🔹 Process ID: %s\n", exec.ID)
 		if prompt := strings.TrimSpace(exec.ExecutorAction.Typ.Prompt); prompt != "" {
 			fmt.Printf("🧑 User Prompt:\n%s\n\n", prompt)
@@ -138,75 +154,128 @@ func showTaskWithMessages(taskID string) error {
 }
 
 func readNormalizedLogs(execID string) error {
-	url := fmt.Sprintf("ws://localhost:8096/api/execution-processes/%s/normalized-logs/ws", execID)
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	entries, err := fetchNormalizedLogs(execID)
 	if err != nil {
-		return fmt.Errorf("error connecting WS: %w", err)
+		return err
 	}
-	defer conn.Close()
-
-	finalEntries := map[int]string{}
+	printLogEntries(os.Stdout, entries)
+	return nil
+}
 
-	for {
-		_, msg, err := conn.ReadMessage()
-		if err != nil {
-			break
-		}
-		if strings.Contains(string(msg), `"finished"`) {
-			break
+// fetchNormalizedLogs replays the normalized-logs stream of an execution
+// process and returns the final conversation.
+func fetchNormalizedLogs(execID string) ([]conversation.Entry, error) {
+	doc := conversation.New()
+	err := apiClient().StreamNormalizedLogs(context.Background(), execID, func(msg api.LogMessage) error {
+		applyLogMessage(doc, msg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]conversation.Entry, 0, doc.Len())
+	for i := 0; i < doc.Len(); i++ {
+		if entry, ok := logEntry(doc, i); ok {
+			entries = append(entries, entry)
 		}
+	}
+	return entries, nil
+}
 
-		var patch struct {
-			JsonPatch []struct {
-				Op    string `json:"op"`
-				Path  string `json:"path"`
-				Value struct {
-					Content struct {
-						EntryType struct {
-							Type string `json:"type"`
-						} `json:"entry_type"`
-						Content string `json:"content"`
-					} `json:"content"`
-				} `json:"value"`
-			} `json:"JsonPatch"`
-		}
+// followNormalizedLogs prints the entries of an execution process while it
+// runs. An entry is printed once a later entry exists, because the server
+// keeps replacing the newest entry while it is being streamed.
+func followNormalizedLogs(ctx context.Context, execID string, w io.Writer, opts logStreamOptions) error {
+	doc := conversation.New()
+	printed := 0
 
-		if err := json.Unmarshal(msg, &patch); err != nil {
-			continue
+	printUpTo := func(end int) {
+		for ; printed < end; printed++ {
+			entry, ok := logEntry(doc, printed)
+			if ok && opts.Filter.keep(entry) {
+				printLogEntries(w, []conversation.Entry{entry})
+			}
 		}
+	}
 
-		for _, p := range patch.JsonPatch {
-			if p.Op != "replace" && p.Op != "add" {
-				continue
-			}
-			var idx int
-			fmt.Sscanf(p.Path, "/entries/%d", &idx)
-			finalEntries[idx] = fmt.Sprintf("%s:%s", p.Value.Content.EntryType.Type, p.Value.Content.Content)
+	err := apiClient().StreamNormalizedLogs(ctx, execID, func(msg api.LogMessage) error {
+		if opts.Idle != nil {
+			opts.Idle.Reset(snapshotIdle)
+		}
+		applyLogMessage(doc, msg)
+		// Entries that were already printed may have been removed.
+		if printed > doc.Len() {
+			printed = doc.Len()
 		}
+		if msg.Finished {
+			printUpTo(doc.Len())
+		} else {
+			printUpTo(doc.Len() - 1)
+		}
+		return nil
+	})
+	printUpTo(doc.Len())
+	return err
+}
+
+// logStreamOptions tune followNormalizedLogs.
+type logStreamOptions struct {
+	Filter logFilter
+	// Idle, when set, is reset on every message so that callers can stop
+	// waiting once a running process goes quiet.
+	Idle *time.Timer
+}
+
+// applyLogMessage applies the patch of a log message to doc. A patch that
+// does not apply is skipped with a warning on stderr, keeping the entries
+// applied so far: one bad message should not hide the rest of the log.
+func applyLogMessage(doc *conversation.Document, msg api.LogMessage) {
+	if len(msg.JSONPatch) == 0 {
+		return
 	}
+	if err := doc.Apply(msg.JSONPatch); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipped a log message: %v\n", err)
+	}
+}
 
-	keys := make([]int, 0, len(finalEntries))
-	for k := range finalEntries {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-
-	for _, k := range keys {
-		entry := finalEntries[k]
-		switch {
-		case strings.HasPrefix(entry, "system_message:"):
-			fmt.Printf("── %s\n", strings.TrimPrefix(entry, "system_message:"))
-		case strings.HasPrefix(entry, "thinking:"):
-			fmt.Printf("── %s\n", strings.TrimPrefix(entry, "thinking:"))
-		case strings.HasPrefix(entry, "tool_use:"):
-			fmt.Printf("── > %s\n", strings.TrimPrefix(entry, "tool_use:"))
-		case strings.HasPrefix(entry, "user_message:"):
-			fmt.Printf("\n> %s\n", strings.TrimPrefix(entry, "user_message:"))
-		case strings.HasPrefix(entry, "assistant_message:"):
-			fmt.Printf("\n✅ 結果:\n%s\n", strings.TrimPrefix(entry, "assistant_message:"))
+// logEntry decodes entry i of doc, warning on stderr about entries that
+// cannot be decoded.
+func logEntry(doc *conversation.Document, i int) (conversation.Entry, bool) {
+	entry, err := doc.Entry(i)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: skipped log entry %d: %v\n", i, err)
+		return conversation.Entry{}, false
+	}
+	return entry, true
+}
+
+func printLogEntries(w io.Writer, entries []conversation.Entry) {
+	p := painter(colorEnabled(w))
+	for _, entry := range entries {
+		switch entry.Type() {
+		case conversation.SystemMessage:
+			fmt.Fprintf(w, "── %s\n", entry.Content)
+		case conversation.Thinking:
+			fmt.Fprintf(w, "── %s\n", entry.Content)
+		case conversation.ToolUse:
+			renderToolUse(w, entry, p)
+		case conversation.UserMessage:
+			fmt.Fprintf(w, "\n> %s\n", entry.Content)
+		case conversation.AssistantMessage:
+			fmt.Fprintf(w, "\n✅ 結果:\n%s\n", entry.Content)
+		case conversation.ErrorMessage:
+			fmt.Fprintf(w, "\n%s\n%s\n", p.paint(ansiRed, "❌ エラー:"), p.paint(ansiRed, entry.Content))
+		case conversation.Loading:
+			fmt.Fprintln(w, p.paint(ansiDim, "── …"))
+		case conversation.TypeStdout:
+			writeIndented(w, strings.TrimRight(entry.Content, "\n"), "")
+		case conversation.TypeStderr:
+			writeIndented(w, p.paint(ansiRed, strings.TrimRight(entry.Content, "\n")), "")
+		default:
+			// Entry types added to the server later are still shown.
+			fmt.Fprintf(w, "── [%s] %s\n", entry.Type(), entry.Content)
 		}
 	}
-	return nil
 }
 
 func sectionDivider(title string) string {
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"testing"
+
+	"vkcli/internal/api"
+)
+
+func TestFetchNormalizedLogsSkipsBadPatches(t *testing.T) {
+	s := &prServer{
+		processes: map[string][]api.ExecutionProcess{
+			"a1": {{ID: "p1", RunReason: "codingagent"}},
+		},
+		logs: map[string][][]interface{}{
+			"p1": {
+				addEntry(0, "user_message", "Expose the column"),
+				patchEntry("replace", 5, "assistant_message", "out of range"),
+				addEntry(1, "tool_use", "cargo test"),
+				{map[string]interface{}{"op": "add", "path": "/entries/2", "value": "not an entry"}},
+				addEntry(3, "assistant_message", "Exposed the column."),
+			},
+		},
+	}
+	s.start(t)
+
+	entries, err := fetchNormalizedLogs("p1")
+	if err != nil {
+		t.Fatalf("fetchNormalizedLogs: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Content)
+	}
+	want := []string{"Expose the column", "cargo test", "Exposed the column."}
+	if len(got) != len(want) {
+		t.Fatalf("got entries %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	message, err := finalAssistantMessage("a1")
+	if err != nil {
+		t.Fatalf("finalAssistantMessage: %v", err)
+	}
+	if message != "Exposed the column." {
+		t.Errorf("got final message %q", message)
+	}
+}
@@ -1,12 +1,10 @@
 package commands
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
+
+	"vkcli/internal/output"
 )
 
 type StatusCommand struct{}
@@ -20,7 +18,7 @@ func (c *StatusCommand) Name() string {
 }
 
 func (c *StatusCommand) Usage() string {
-	return "vkcli status <task_id|attempt_id>"
+	return "vkcli status <task_id|attempt_id> [--output <fmt>]"
 }
 
 func (c *StatusCommand) Description() string {
@@ -28,39 +26,53 @@ func (c *StatusCommand) Description() string {
 }
 
 func (c *StatusCommand) Run(args []string) error {
+	args, format, err := parseOutputFlags(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 1 {
-		return fmt.Errorf("Usage: vkcli status <task_id|attempt_id>")
+		return fmt.Errorf("Usage: vkcli status <task_id|attempt_id> [--output <fmt>]")
 	}
 	targetID := args[0]
 
+	result := statusResult{ID: targetID, Kind: "task"}
 	if status, err := getTaskStatusByID(targetID); err == nil {
-		fmt.Printf("Task %s status: %s\n", targetID, status)
-		return nil
+		result.Status = status
+	} else {
+		result.Kind = "attempt"
+		result.Status = getAttemptStatus(targetID)
+	}
+
+	if !format.IsTable() {
+		cols := output.Columns{
+			Headers: []string{"id", "kind", "status"},
+			Rows:    [][]string{{result.ID, result.Kind, result.Status}},
+		}
+		return writeOutput(format, result, cols)
 	}
 
-	status := getAttemptStatus(targetID)
-	fmt.Printf("Attempt %s status: %s\n", targetID, status)
+	if result.Kind == "task" {
+		fmt.Printf("Task %s status: %s\n", targetID, result.Status)
+	} else {
+		fmt.Printf("Attempt %s status: %s\n", targetID, result.Status)
+	}
 	return nil
 }
 
-func getTaskStatusByID(taskID string) (string, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/tasks/%s", baseURL, taskID))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+// statusResult is the machine-readable form of the status command.
+type statusResult struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+}
 
-	body, err := io.ReadAll(resp.Body)
+func getTaskStatusByID(taskID string) (string, error) {
+	task, err := apiClient().GetTask(taskID)
 	if err != nil {
 		return "", err
 	}
 
-	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
-	}
-
-	status := parseStatusFromBody(body)
-	normalized := normalizeStatusString(status)
+	normalized := normalizeStatusString(task.Status)
 	if normalized == "" {
 		return "", fmt.Errorf("status not found")
 	}
@@ -75,128 +87,22 @@ func getTaskStatus(taskID string) string {
 	return status
 }
 
+// getAttemptStatus reports the status of the task an attempt belongs to;
+// attempts themselves carry no status on the server.
 func getAttemptStatus(attemptID string) string {
-	taskID, status, err := fetchAttemptMetadata(attemptID)
-	if err == nil {
-		if normalized := normalizeStatusString(status); normalized != "" {
-			return normalized
-		}
-		if taskID != "" {
-			if taskStatus, err := getTaskStatusByID(taskID); err == nil {
-				return taskStatus
-			}
-		}
-	}
-
-	if taskID != "" {
-		if taskStatus, err := getTaskStatusByID(taskID); err == nil {
-			return taskStatus
-		}
+	taskID, err := fetchAttemptTaskID(attemptID)
+	if err != nil || taskID == "" {
+		return "UNKNOWN"
 	}
-
-	return "UNKNOWN"
+	return getTaskStatus(taskID)
 }
 
-func fetchAttemptMetadata(attemptID string) (taskID, status string, err error) {
-	resp, err := http.Get(fmt.Sprintf("%s/task-attempts/%s", baseURL, attemptID))
+func fetchAttemptTaskID(attemptID string) (string, error) {
+	attempt, err := apiClient().GetAttempt(attemptID)
 	if err != nil {
-		return "", "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", err
-	}
-
-	if resp.StatusCode >= 400 {
-		return "", "", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
-	}
-
-	var payload interface{}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return "", "", err
-	}
-
-	status = parseStatusFromInterface(payload)
-	taskID = extractTaskIDFromPayload(payload)
-	return taskID, status, nil
-}
-
-func parseStatusFromBody(body []byte) string {
-	trimmed := bytes.TrimSpace(body)
-	if len(trimmed) == 0 {
-		return ""
-	}
-
-	var payload interface{}
-	if err := json.Unmarshal(trimmed, &payload); err == nil {
-		if status := parseStatusFromInterface(payload); status != "" {
-			return status
-		}
-	}
-
-	raw := strings.Trim(string(trimmed), "\"")
-	return raw
-}
-
-func parseStatusFromInterface(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return val
-	case map[string]interface{}:
-		return parseStatusFromMap(val)
-	case []interface{}:
-		for _, item := range val {
-			if status := parseStatusFromInterface(item); status != "" {
-				return status
-			}
-		}
-	}
-	return ""
-}
-
-func parseStatusFromMap(m map[string]interface{}) string {
-	for _, key := range []string{"status", "branch_status", "branchStatus"} {
-		if value, ok := m[key]; ok {
-			if status := parseStatusFromInterface(value); status != "" {
-				return status
-			}
-		}
-	}
-
-	for _, value := range m {
-		if status := parseStatusFromInterface(value); status != "" {
-			return status
-		}
-	}
-	return ""
-}
-
-func extractTaskIDFromPayload(payload interface{}) string {
-	switch val := payload.(type) {
-	case map[string]interface{}:
-		if data, ok := val["data"]; ok {
-			if id := extractTaskIDFromPayload(data); id != "" {
-				return id
-			}
-		}
-		if id, ok := val["task_id"].(string); ok && id != "" {
-			return id
-		}
-		if task, ok := val["task"].(map[string]interface{}); ok {
-			if id, ok := task["id"].(string); ok && id != "" {
-				return id
-			}
-		}
-	case []interface{}:
-		for _, item := range val {
-			if id := extractTaskIDFromPayload(item); id != "" {
-				return id
-			}
-		}
+		return "", err
 	}
-	return ""
+	return attempt.TaskID, nil
 }
 
 func normalizeStatusString(status string) string {
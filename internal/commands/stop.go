@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"vkcli/internal/api"
+)
+
+const stopUsage = "vkcli stop <attempt_id|task_id> | vkcli stop --all <project_id> [--yes]"
+
+type StopCommand struct{}
+
+func NewStopCommand() Command {
+	return &StopCommand{}
+}
+
+func (c *StopCommand) Name() string {
+	return "stop"
+}
+
+func (c *StopCommand) Usage() string {
+	return stopUsage
+}
+
+func (c *StopCommand) Description() string {
+	return "実行中のエージェントを停止"
+}
+
+func (c *StopCommand) Run(args []string) error {
+	var target string
+	all, yes := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			all = true
+		case arg == "--yes" || arg == "-y":
+			yes = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if target != "" {
+				return fmt.Errorf("Usage: %s", stopUsage)
+			}
+			target = strings.TrimSpace(arg)
+		}
+	}
+	if target == "" {
+		return fmt.Errorf("Usage: %s", stopUsage)
+	}
+
+	client := apiClient()
+	var attemptIDs []string
+	var err error
+	if all {
+		attemptIDs, err = projectAttemptIDs(client, target)
+		if err != nil {
+			return err
+		}
+		if len(attemptIDs) > 0 && !yes {
+			ok, err := confirm(fmt.Sprintf("Stop every running agent in project %s?", target))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Aborted.")
+				return nil
+			}
+		}
+	} else {
+		attemptIDs, err = stopTargetAttemptIDs(client, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	stoppedAny := false
+	for _, attemptID := range attemptIDs {
+		stopped, err := stopRunningProcesses(attemptID)
+		for _, id := range stopped {
+			fmt.Printf("Stopped process: %s (attempt %s)\n", id, attemptID)
+			stoppedAny = true
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !stoppedAny {
+		fmt.Println("No running execution processes.")
+	}
+	return nil
+}
+
+// stopTargetAttemptIDs resolves the attempts to stop for an attempt ID or,
+// for a task ID, every attempt of the task.
+func stopTargetAttemptIDs(client *api.Client, id string) ([]string, error) {
+	if task, err := client.GetTask(id); err == nil {
+		return listTaskAttemptIDs(task.ID)
+	}
+	if attempt, err := client.GetAttempt(id); err == nil {
+		return []string{attempt.ID}, nil
+	}
+	return nil, fmt.Errorf("%s is not a task or attempt", id)
+}
+
+// projectAttemptIDs returns the attempts of every task in a project that
+// the server reports as in progress.
+func projectAttemptIDs(client *api.Client, projectID string) ([]string, error) {
+	tasks, err := client.ListTasks(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, task := range tasks {
+		if !task.HasInProgressAttempt && !strings.EqualFold(task.Status, "inprogress") {
+			continue
+		}
+		attemptIDs, err := listTaskAttemptIDs(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, attemptIDs...)
+	}
+	return ids, nil
+}
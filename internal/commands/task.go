@@ -0,0 +1,341 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"vkcli/internal/api"
+)
+
+const taskCreateUsage = "vkcli task create <project_id> --title <title> " +
+	"[--description <text> | --description-file <path> | --editor] [--output <fmt>]"
+
+const taskEditUsage = "vkcli task edit <task_id> [--title <title>] " +
+	"[--description <text> | --description-file <path>] [--status <status>] [--editor]"
+
+const taskDeleteUsage = "vkcli task delete <task_id> [--yes]"
+
+// taskStatuses are the statuses accepted by the server, in board order.
+var taskStatuses = []string{"todo", "inprogress", "inreview", "done", "cancelled"}
+
+type TaskCommand struct{}
+
+func NewTaskCommand() Command {
+	return &TaskCommand{}
+}
+
+func (c *TaskCommand) Name() string {
+	return "task"
+}
+
+func (c *TaskCommand) Usage() string {
+	return "vkcli task <create|edit|delete> ..."
+}
+
+func (c *TaskCommand) Description() string {
+	return "タスクの作成・編集・削除"
+}
+
+func (c *TaskCommand) Run(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("Usage:\n  %s\n  %s\n  %s", taskCreateUsage, taskEditUsage, taskDeleteUsage)
+	}
+	switch args[0] {
+	case "create":
+		return runTaskCreate(args[1:])
+	case "edit":
+		return runTaskEdit(args[1:])
+	case "delete":
+		return runTaskDelete(args[1:])
+	default:
+		return fmt.Errorf("unknown task subcommand: %s", args[0])
+	}
+}
+
+func runTaskCreate(args []string) error {
+	args, format, err := parseOutputFlags(args)
+	if err != nil {
+		return err
+	}
+
+	var projectID, title, description, descriptionFile string
+	useEditor := false
+	sources := 0
+	for i := 0; i < len(args); i++ {
+		if v, ok, err := takeFlagValue(args, &i, "--title"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			title = v
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--description"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			description = v
+			sources++
+			continue
+		}
+		if v, ok, err := takeFlagValue(args, &i, "--description-file"); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			descriptionFile = v
+			sources++
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--editor":
+			useEditor = true
+			sources++
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case projectID != "":
+			return fmt.Errorf("multiple project IDs specified")
+		default:
+			projectID = strings.TrimSpace(arg)
+		}
+	}
+
+	if projectID == "" {
+		return fmt.Errorf("Usage: %s", taskCreateUsage)
+	}
+	if sources > 1 {
+		return fmt.Errorf("--description, --description-file and --editor are mutually exclusive")
+	}
+
+	switch {
+	case descriptionFile != "":
+		description, err = readDescriptionFile(descriptionFile)
+		if err != nil {
+			return err
+		}
+	case useEditor:
+		edited, err := editText(taskDocument{Title: title}.String(), "vkcli-task-*.md")
+		if err != nil {
+			return err
+		}
+		doc, err := parseTaskDocument(edited)
+		if err != nil {
+			return err
+		}
+		if doc.Title != "" {
+			title = doc.Title
+		}
+		description = doc.Description
+	}
+
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("a title is required (--title)")
+	}
+
+	task, err := apiClient().CreateTask(api.CreateTaskRequest{
+		ProjectID:   projectID,
+		Title:       strings.TrimSpace(title),
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	if !format.IsTable() {
+		return writeOutput(format, task, taskColumns([]api.Task{*task}))
+	}
+	fmt.Printf("Created task: %s\n", task.ID)
+	return nil
+}
+
+func runTaskEdit(args []string) error {
+	args, format, err := parseOutputFlags(args)
+	if err != nil {
+		return err
+	}
+
+	var taskID string
+	var title, description, descriptionFile, status *string
+	useEditor := false
+	for i := 0; i < len(args); i++ {
+		matched := false
+		for _, f := range []struct {
+			name   string
+			target **string
+		}{
+			{"--title", &title},
+			{"--description", &description},
+			{"--description-file", &descriptionFile},
+			{"--status", &status},
+		} {
+			v, ok, err := takeFlagValue(args, &i, f.name)
+			if err != nil {
+				return err
+			}
+			if ok {
+				*f.target = &v
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		switch arg := args[i]; {
+		case arg == "--editor":
+			useEditor = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case taskID != "":
+			return fmt.Errorf("multiple task IDs specified")
+		default:
+			taskID = strings.TrimSpace(arg)
+		}
+	}
+	if taskID == "" {
+		return fmt.Errorf("Usage: %s", taskEditUsage)
+	}
+	if description != nil && descriptionFile != nil {
+		return fmt.Errorf("--description and --description-file are mutually exclusive")
+	}
+	if title == nil && description == nil && descriptionFile == nil && status == nil {
+		useEditor = true
+	}
+
+	client := apiClient()
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	req := api.UpdateTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+	}
+	if title != nil {
+		req.Title = strings.TrimSpace(*title)
+	}
+	if description != nil {
+		req.Description = *description
+	}
+	if descriptionFile != nil {
+		if req.Description, err = readDescriptionFile(*descriptionFile); err != nil {
+			return err
+		}
+	}
+	if status != nil {
+		req.Status = *status
+	}
+
+	if useEditor {
+		edited, err := editText(taskDocument{
+			Title:       req.Title,
+			Status:      req.Status,
+			Description: req.Description,
+		}.String(), "vkcli-task-*.md")
+		if err != nil {
+			return err
+		}
+		doc, err := parseTaskDocument(edited)
+		if err != nil {
+			return err
+		}
+		req.Title = doc.Title
+		req.Description = doc.Description
+		if doc.Status != "" {
+			req.Status = doc.Status
+		}
+	}
+
+	if req.Status, err = parseTaskStatus(req.Status); err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+	if req.Title == task.Title && req.Description == task.Description && req.Status == task.Status {
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	updated, err := client.UpdateTask(taskID, req)
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	if !format.IsTable() {
+		return writeOutput(format, updated, taskColumns([]api.Task{*updated}))
+	}
+	fmt.Printf("Updated task: %s\n", updated.ID)
+	return nil
+}
+
+func runTaskDelete(args []string) error {
+	var taskID string
+	assumeYes := false
+	for _, arg := range args {
+		switch {
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case taskID != "":
+			return fmt.Errorf("multiple task IDs specified")
+		default:
+			taskID = strings.TrimSpace(arg)
+		}
+	}
+	if taskID == "" {
+		return fmt.Errorf("Usage: %s", taskDeleteUsage)
+	}
+
+	client := apiClient()
+	task, err := client.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	if !assumeYes {
+		ok, err := confirm(fmt.Sprintf("Delete task %s (%s)?", task.ID, task.Title))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := client.DeleteTask(taskID); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	fmt.Printf("Deleted task: %s\n", task.ID)
+	return nil
+}
+
+// parseTaskStatus maps user input such as "In-Review" to the server's
+// lowercase status names.
+func parseTaskStatus(status string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(normalizeStatusString(status), "_", ""))
+	for _, s := range taskStatuses {
+		if s == normalized {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid status %q (expected %s)", status, strings.Join(taskStatuses, ", "))
+}
+
+// readDescriptionFile reads a description from path, or from stdin for "-".
+func readDescriptionFile(path string) (string, error) {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vkcli/internal/api"
+)
+
+// taskFilter selects and orders tasks on the client side; the tasks
+// endpoint itself only filters by project.
+type taskFilter struct {
+	Statuses     []string
+	TitleMatch   *regexp.Regexp
+	CreatedSince time.Time
+	UpdatedSince time.Time
+	Sort         string
+	Reverse      bool
+	Limit        int
+}
+
+// taskStatusOrder is the board column order used when sorting by status.
+var taskStatusOrder = map[string]int{
+	"TODO":       0,
+	"INPROGRESS": 1,
+	"INREVIEW":   2,
+	"DONE":       3,
+	"CANCELLED":  4,
+}
+
+// parseFlag consumes args[*i] if it is one of the filter flags.
+func (f *taskFilter) parseFlag(args []string, i *int) (bool, error) {
+	if v, ok, err := takeFlagValue(args, i, "--status"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		for _, s := range strings.Split(v, ",") {
+			if s = normalizeStatusString(s); s != "" {
+				f.Statuses = append(f.Statuses, s)
+			}
+		}
+		return true, nil
+	}
+	if v, ok, err := takeFlagValue(args, i, "--title-match"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return true, fmt.Errorf("invalid --title-match: %w", err)
+		}
+		f.TitleMatch = re
+		return true, nil
+	}
+	if v, ok, err := takeFlagValue(args, i, "--created-since"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		f.CreatedSince, err = parseSince(v, time.Now())
+		return true, err
+	}
+	if v, ok, err := takeFlagValue(args, i, "--updated-since"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		f.UpdatedSince, err = parseSince(v, time.Now())
+		return true, err
+	}
+	if v, ok, err := takeFlagValue(args, i, "--sort"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		switch v {
+		case "created", "updated", "title", "status":
+			f.Sort = v
+		default:
+			return true, fmt.Errorf("invalid --sort %q (expected created, updated, title or status)", v)
+		}
+		return true, nil
+	}
+	if v, ok, err := takeFlagValue(args, i, "--limit"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return true, fmt.Errorf("invalid --limit %q", v)
+		}
+		f.Limit = n
+		return true, nil
+	}
+	if args[*i] == "--reverse" {
+		f.Reverse = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// apply returns the matching tasks in the requested order.
+func (f *taskFilter) apply(tasks []api.Task) []api.Task {
+	matched := make([]api.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if f.matches(t) {
+			matched = append(matched, t)
+		}
+	}
+
+	var less func(a, b api.Task) bool
+	switch f.Sort {
+	case "created":
+		less = func(a, b api.Task) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "updated":
+		less = func(a, b api.Task) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	case "title":
+		less = func(a, b api.Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case "status":
+		less = func(a, b api.Task) bool { return statusRank(a.Status) < statusRank(b.Status) }
+	}
+	if less != nil {
+		sort.SliceStable(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+	}
+	if f.Reverse {
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched
+}
+
+func (f *taskFilter) matches(t api.Task) bool {
+	if len(f.Statuses) > 0 {
+		status := normalizeStatusString(t.Status)
+		found := false
+		for _, s := range f.Statuses {
+			if s == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.TitleMatch != nil && !f.TitleMatch.MatchString(t.Title) {
+		return false
+	}
+	if !f.CreatedSince.IsZero() && t.CreatedAt.Before(f.CreatedSince) {
+		return false
+	}
+	if !f.UpdatedSince.IsZero() && t.UpdatedAt.Before(f.UpdatedSince) {
+		return false
+	}
+	return true
+}
+
+func statusRank(status string) int {
+	if rank, ok := taskStatusOrder[normalizeStatusString(status)]; ok {
+		return rank
+	}
+	return len(taskStatusOrder)
+}
@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// watchOptions control how exec and followup wait for a started attempt.
+type watchOptions struct {
+	Follow  bool
+	JSON    bool
+	Timeout time.Duration
+	Detach  bool
+}
+
+// parseFlag consumes args[*i] if it is a watch flag and reports whether it
+// did.
+func (o *watchOptions) parseFlag(args []string, i *int) (bool, error) {
+	switch arg := args[*i]; {
+	case arg == "--follow" || arg == "-f":
+		o.Follow = true
+		return true, nil
+	case arg == "--json":
+		o.JSON = true
+		return true, nil
+	case arg == "--detach":
+		o.Detach = true
+		return true, nil
+	}
+	if v, ok, err := takeFlagValue(args, i, "--timeout"); ok || err != nil {
+		if err != nil {
+			return true, err
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil || timeout <= 0 {
+			return true, fmt.Errorf("invalid --timeout %q: use a duration such as 45m or 2h", v)
+		}
+		o.Timeout = timeout
+		return true, nil
+	}
+	return false, nil
+}
+
+func (o watchOptions) validate() error {
+	if o.Detach && (o.Follow || o.Timeout > 0) {
+		return fmt.Errorf("--detach cannot be combined with --follow or --timeout")
+	}
+	return nil
+}
+
+// progress is where monitoring output goes. With --json, stdout carries
+// only the result object, so progress goes to stderr.
+func (o watchOptions) progress() io.Writer {
+	if o.JSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// attemptResult is the final outcome of an exec or followup run, printed by
+// --json.
+type attemptResult struct {
+	TaskID          string    `json:"task_id"`
+	AttemptID       string    `json:"attempt_id"`
+	Attempts        []string  `json:"attempts,omitempty"`
+	Status          string    `json:"status"`
+	Outcome         string    `json:"outcome"`
+	ExitCode        int       `json:"exit_code"`
+	Branch          string    `json:"branch,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at,omitzero"`
+	Duration        string    `json:"duration,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+}
+
+// watchAttempt monitors a started attempt with awaitAttempt and reports
+// the outcome.
+func watchAttempt(taskID, attemptID string, startedAt time.Time, skip map[string]bool, opts watchOptions) error {
+	status, outcome, code, err := awaitAttempt(taskID, attemptID, skip, opts)
+	if err != nil {
+		return err
+	}
+	return reportResult(opts, attemptResult{
+		TaskID:    taskID,
+		AttemptID: attemptID,
+		Status:    status,
+		Outcome:   outcome,
+		ExitCode:  code,
+		StartedAt: startedAt,
+	})
+}
+
+// awaitAttempt monitors a started attempt until its task reaches a final
+// status, the timeout elapses or the user interrupts, and returns the final
+// task status and the outcome. Processes in skip are not streamed by
+// --follow.
+func awaitAttempt(taskID, attemptID string, skip map[string]bool, opts watchOptions) (status, outcome string, code int, err error) {
+	progress := opts.progress()
+	ctx, cancel := monitorContext(opts.Timeout)
+	defer cancel()
+
+	status, err = monitorAttempt(ctx, progress, taskID, attemptID, monitorOptions{Follow: opts.Follow, Skip: skip})
+	switch {
+	case ctx.Err() != nil:
+		outcome, code = abortAttempt(ctx, progress, attemptID, opts.Timeout)
+		if status, err = getTaskStatusByID(taskID); err != nil {
+			status = "UNKNOWN"
+		}
+		return status, outcome, code, nil
+	case err != nil:
+		return status, "", 0, err
+	}
+	outcome, code = attemptOutcome(attemptID, status)
+	return status, outcome, code, nil
+}
+
+// abortAttempt handles monitoring that ended before the attempt finished.
+// On timeout the running processes are stopped; on SIGINT/SIGTERM the user
+// is asked first.
+func abortAttempt(ctx context.Context, progress io.Writer, attemptID string, timeout time.Duration) (outcome string, code int) {
+	outcome, code = "cancelled", ExitCancelled
+	stop := true
+	if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		outcome, code = "timeout", ExitTimeout
+		fmt.Fprintf(progress, "Timed out after %s\n", timeout)
+	} else {
+		var err error
+		stop, err = confirm("Stop the running execution process on the server?")
+		if err != nil {
+			stop = false
+		}
+	}
+
+	if !stop {
+		fmt.Fprintf(progress, "The agent keeps running; check it with: vkcli status %s\n", attemptID)
+		return outcome, code
+	}
+	stopped, err := stopRunningProcesses(attemptID)
+	for _, id := range stopped {
+		fmt.Fprintf(progress, "Stopped process: %s\n", id)
+	}
+	if err != nil {
+		fmt.Fprintf(progress, "Error: %v\n", err)
+	}
+	return outcome, code
+}
+
+// reportDetached prints the attempt started by --detach, prefixed with
+// label, without waiting for it.
+func reportDetached(opts watchOptions, label, taskID, attemptID string, startedAt time.Time) error {
+	if !opts.JSON {
+		fmt.Printf("%s: %s\n", label, attemptID)
+		return nil
+	}
+
+	result := attemptResult{
+		TaskID:    taskID,
+		AttemptID: attemptID,
+		Status:    "INPROGRESS",
+		Outcome:   "detached",
+		StartedAt: startedAt.UTC(),
+	}
+	if attempt, err := apiClient().GetAttempt(attemptID); err == nil {
+		result.Branch = attempt.Branch
+	}
+	return printJSON(result)
+}
+
+// reportResult completes result, prints the summary line (and the --json
+// object) and turns the outcome into the command's exit code.
+func reportResult(opts watchOptions, result attemptResult) error {
+	finishedAt := time.Now()
+	duration := finishedAt.Sub(result.StartedAt).Round(time.Second)
+	result.StartedAt = result.StartedAt.UTC()
+	result.FinishedAt = finishedAt.UTC()
+	result.Duration = duration.String()
+	result.DurationSeconds = duration.Seconds()
+	if attempt, err := apiClient().GetAttempt(result.AttemptID); err == nil {
+		result.Branch = attempt.Branch
+	}
+
+	branch := result.Branch
+	if branch == "" {
+		branch = "-"
+	}
+	fmt.Fprintf(opts.progress(), "Result: %s (%s) attempt=%s branch=%s duration=%s",
+		result.Outcome, result.Status, result.AttemptID, branch, result.Duration)
+	if len(result.Attempts) > 1 {
+		fmt.Fprintf(opts.progress(), " attempts=%s", strings.Join(result.Attempts, ","))
+	}
+	fmt.Fprintln(opts.progress())
+
+	if opts.JSON {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	}
+	if result.ExitCode != ExitOK {
+		return &ExitError{Code: result.ExitCode}
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
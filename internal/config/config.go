@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultServer is used when neither the flag, the environment nor the
+// config file specify a server.
+const DefaultServer = "http://localhost:8096"
+
+// ServerEnv is the environment variable that overrides the config file.
+const ServerEnv = "VKCLI_SERVER"
+
+// Source describes where a resolved value came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "config file"
+	SourceEnv     Source = "env " + ServerEnv
+	SourceFlag    Source = "--server flag"
+)
+
+// Config holds the resolved vkcli settings.
+type Config struct {
+	// Server is the normalized server root, e.g. "http://localhost:8096".
+	Server string
+	// ServerSource tells which layer supplied Server.
+	ServerSource Source
+	// Path is the config file location, whether or not it exists.
+	Path string
+	// FileLoaded reports whether Path was found and parsed.
+	FileLoaded bool
+	// Defaults are the top-level executor settings of the config file.
+	Defaults ExecDefaults
+	// Projects holds the [projects."<id>"] sections by project ID.
+	Projects map[string]ExecDefaults
+	// Pager is the command that vkcli diff pipes its output to, e.g.
+	// "delta". Empty means $PAGER.
+	Pager string
+}
+
+// ExecDefaults are the settings used for new attempts when the command line
+// does not give them. Empty fields are unset.
+type ExecDefaults struct {
+	Executor   string
+	Variant    string
+	BaseBranch string
+}
+
+// projectSectionPrefix starts the section names of per-project defaults.
+const projectSectionPrefix = "projects."
+
+func execDefaultsFrom(section map[string]string) ExecDefaults {
+	return ExecDefaults{
+		Executor:   strings.TrimSpace(section["executor"]),
+		Variant:    strings.TrimSpace(section["variant"]),
+		BaseBranch: strings.TrimSpace(section["base_branch"]),
+	}
+}
+
+// ExecDefaultsFor returns the defaults for a project: its own section
+// first, then the top-level keys.
+func (c *Config) ExecDefaultsFor(projectID string) ExecDefaults {
+	d := c.Defaults
+	p, ok := c.Projects[projectID]
+	if !ok {
+		return d
+	}
+	if p.Executor != "" {
+		d.Executor = p.Executor
+		// A variant belongs to its executor.
+		d.Variant = p.Variant
+	} else if p.Variant != "" {
+		d.Variant = p.Variant
+	}
+	if p.BaseBranch != "" {
+		d.BaseBranch = p.BaseBranch
+	}
+	return d
+}
+
+// Default returns the configuration used when nothing is configured.
+func Default() *Config {
+	return &Config{
+		Server:       DefaultServer,
+		ServerSource: SourceDefault,
+		Path:         DefaultPath(),
+		Projects:     map[string]ExecDefaults{},
+	}
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/vkcli/config.toml, falling back to
+// ~/.config/vkcli/config.toml.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(".config", "vkcli", "config.toml")
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "vkcli", "config.toml")
+}
+
+// Load resolves the configuration. Precedence, highest first:
+// the --server flag, $VKCLI_SERVER, the config file, then DefaultServer.
+func Load(flagServer string) (*Config, error) {
+	cfg := Default()
+
+	f, err := os.Open(cfg.Path)
+	switch {
+	case err == nil:
+		doc, parseErr := parseTOML(f)
+		f.Close()
+		if parseErr != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Path, parseErr)
+		}
+		cfg.FileLoaded = true
+		if server := doc[""]["server"]; server != "" {
+			cfg.Server = server
+			cfg.ServerSource = SourceFile
+		}
+		cfg.Defaults = execDefaultsFrom(doc[""])
+		cfg.Pager = strings.TrimSpace(doc[""]["pager"])
+		for name, section := range doc {
+			if id, ok := strings.CutPrefix(name, projectSectionPrefix); ok && id != "" {
+				cfg.Projects[id] = execDefaultsFrom(section)
+			}
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	if server := strings.TrimSpace(os.Getenv(ServerEnv)); server != "" {
+		cfg.Server = server
+		cfg.ServerSource = SourceEnv
+	}
+	if server := strings.TrimSpace(flagServer); server != "" {
+		cfg.Server = server
+		cfg.ServerSource = SourceFlag
+	}
+
+	normalized, err := normalizeServer(cfg.Server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server %q (from %s): %w", cfg.Server, cfg.ServerSource, err)
+	}
+	cfg.Server = normalized
+	return cfg, nil
+}
+
+// APIBaseURL returns the HTTP API root, e.g. "http://localhost:8096/api".
+func (c *Config) APIBaseURL() string {
+	return c.Server + "/api"
+}
+
+// WSBaseURL returns the websocket API root, e.g. "ws://localhost:8096/api".
+func (c *Config) WSBaseURL() string {
+	switch {
+	case strings.HasPrefix(c.Server, "https://"):
+		return "wss://" + strings.TrimPrefix(c.Server, "https://") + "/api"
+	default:
+		return "ws://" + strings.TrimPrefix(c.Server, "http://") + "/api"
+	}
+}
+
+// normalizeServer accepts "host:port", "http://host:port" or
+// "http://host:port/api" and returns the scheme://host[:port][/prefix] form
+// without a trailing slash or /api suffix.
+func normalizeServer(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("empty server")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("missing host")
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	path = strings.TrimSuffix(path, "/api")
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, path), nil
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// document is the parsed form of a config file: section name to key/value
+// pairs. Keys outside any section live under the empty section name.
+type document map[string]map[string]string
+
+// parseTOML reads the small subset of TOML used by vkcli config files:
+// comments, [section] / [section."quoted.name"] headers and key = value pairs
+// whose values are strings, numbers or booleans.
+func parseTOML(r io.Reader) (document, error) {
+	doc := document{"": {}}
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNo)
+			}
+			name, err := parseSectionName(strings.TrimSpace(line[1 : len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			section = name
+			if _, ok := doc[section]; !ok {
+				doc[section] = map[string]string{}
+			}
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key := unquote(strings.TrimSpace(line[:eq]))
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", lineNo)
+		}
+		value, err := parseValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		doc[section][key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseSectionName turns `projects."abc-123"` into `projects.abc-123`.
+func parseSectionName(raw string) (string, error) {
+	var parts []string
+	for raw != "" {
+		raw = strings.TrimSpace(raw)
+		var part string
+		if strings.HasPrefix(raw, "\"") {
+			end := strings.Index(raw[1:], "\"")
+			if end < 0 {
+				return "", fmt.Errorf("unterminated quoted section name")
+			}
+			part = raw[1 : end+1]
+			raw = strings.TrimSpace(raw[end+2:])
+		} else {
+			end := strings.Index(raw, ".")
+			if end < 0 {
+				end = len(raw)
+			}
+			part = strings.TrimSpace(raw[:end])
+			raw = raw[end:]
+		}
+		if part == "" {
+			return "", fmt.Errorf("empty section name")
+		}
+		parts = append(parts, part)
+		if raw == "" {
+			break
+		}
+		if !strings.HasPrefix(raw, ".") {
+			return "", fmt.Errorf("invalid section name")
+		}
+		raw = raw[1:]
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("empty section name")
+	}
+	return strings.Join(parts, "."), nil
+}
+
+func parseValue(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, "\""):
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", raw)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("invalid string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case raw == "true" || raw == "false":
+		return raw, nil
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64); err == nil {
+		return raw, nil
+	}
+	return "", fmt.Errorf("unsupported value %s", raw)
+}
+
+// stripComment removes a trailing # comment that is not inside a string.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if v, err := strconv.Unquote(s); err == nil {
+			return v
+		}
+	}
+	return s
+}
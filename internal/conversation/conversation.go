@@ -0,0 +1,264 @@
+// Package conversation rebuilds the normalized conversation of an execution
+// process from the JSON Patch stream sent by the server.
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"vkcli/internal/jsonpatch"
+)
+
+// Patch kinds of the values stored in the entries array.
+const (
+	KindNormalized = "NORMALIZED_ENTRY"
+	KindStdout     = "STDOUT"
+	KindStderr     = "STDERR"
+)
+
+// Entry types of normalized entries.
+const (
+	UserMessage      = "user_message"
+	AssistantMessage = "assistant_message"
+	ToolUse          = "tool_use"
+	SystemMessage    = "system_message"
+	ErrorMessage     = "error_message"
+	Thinking         = "thinking"
+	Loading          = "loading"
+)
+
+// Types that Entry.Type reports for raw output entries.
+const (
+	TypeStdout = "stdout"
+	TypeStderr = "stderr"
+)
+
+// Types lists every value Entry.Type can report for known entries.
+var Types = []string{
+	UserMessage, AssistantMessage, ToolUse, SystemMessage, ErrorMessage, Thinking, Loading,
+	TypeStdout, TypeStderr,
+}
+
+// Entry is one element of the conversation.
+type Entry struct {
+	// Kind is the patch kind, usually KindNormalized.
+	Kind      string          `json:"kind"`
+	Timestamp *time.Time      `json:"timestamp,omitempty"`
+	EntryType EntryType       `json:"entry_type"`
+	Content   string          `json:"content"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Type returns the entry type, or the lowercased kind for raw output.
+func (e Entry) Type() string {
+	switch e.Kind {
+	case KindStdout:
+		return TypeStdout
+	case KindStderr:
+		return TypeStderr
+	}
+	return e.EntryType.Type
+}
+
+// EntryType describes a normalized entry. ToolName, ActionType and Status
+// are only set for tool_use entries.
+type EntryType struct {
+	Type       string          `json:"type"`
+	ToolName   string          `json:"tool_name,omitempty"`
+	ActionType *ActionType     `json:"action_type,omitempty"`
+	Status     json.RawMessage `json:"status,omitempty"`
+}
+
+// ActionType is the structured form of a tool call. Which fields are set
+// depends on Action.
+type ActionType struct {
+	Action string `json:"action"`
+
+	// file_read, file_edit
+	Path    string       `json:"path,omitempty"`
+	Changes []FileChange `json:"changes,omitempty"`
+
+	// command_run
+	Command string         `json:"command,omitempty"`
+	Result  *CommandResult `json:"result,omitempty"`
+
+	// search, web_fetch
+	Query string `json:"query,omitempty"`
+	URL   string `json:"url,omitempty"`
+
+	// tool
+	ToolName   string          `json:"tool_name,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	ToolResult json.RawMessage `json:"tool_result,omitempty"`
+
+	// task_create, other
+	Description string `json:"description,omitempty"`
+
+	// plan_presentation
+	Plan string `json:"plan,omitempty"`
+
+	// todo_management
+	Todos     []Todo `json:"todos,omitempty"`
+	Operation string `json:"operation,omitempty"`
+}
+
+// UnmarshalJSON decodes "result" as a CommandResult for command_run and
+// keeps it verbatim for generic tool calls, whose results are free-form.
+func (a *ActionType) UnmarshalJSON(raw []byte) error {
+	type plain ActionType
+	var v struct {
+		plain
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+	*a = ActionType(v.plain)
+	if len(v.Result) == 0 || string(v.Result) == "null" {
+		return nil
+	}
+	if a.Action == "command_run" {
+		var result CommandResult
+		if err := json.Unmarshal(v.Result, &result); err == nil {
+			a.Result = &result
+			return nil
+		}
+	}
+	a.ToolResult = v.Result
+	return nil
+}
+
+// FileChange is one change of a file_edit action.
+type FileChange struct {
+	Action         string `json:"action"`
+	Content        string `json:"content,omitempty"`
+	UnifiedDiff    string `json:"unified_diff,omitempty"`
+	HasLineNumbers bool   `json:"has_line_numbers,omitempty"`
+	NewPath        string `json:"new_path,omitempty"`
+}
+
+// CommandResult is the outcome of a command_run action.
+type CommandResult struct {
+	ExitStatus *ExitStatus `json:"exit_status,omitempty"`
+	Output     string      `json:"output,omitempty"`
+}
+
+// ExitStatus is either {"type":"exit_code","code":N} or
+// {"type":"success","success":bool}.
+type ExitStatus struct {
+	Type    string `json:"type"`
+	Code    *int   `json:"code,omitempty"`
+	Success *bool  `json:"success,omitempty"`
+}
+
+// Todo is an item of a todo_management action.
+type Todo struct {
+	Content  string `json:"content"`
+	Status   string `json:"status"`
+	Priority string `json:"priority,omitempty"`
+}
+
+// Document is the patched conversation: {"entries": [...]}.
+type Document struct {
+	root interface{}
+}
+
+// New returns an empty conversation.
+func New() *Document {
+	return &Document{root: map[string]interface{}{"entries": []interface{}{}}}
+}
+
+// Apply applies one JSON Patch message. A failing patch leaves the
+// document unchanged.
+func (d *Document) Apply(ops []jsonpatch.Operation) error {
+	root, err := jsonpatch.Apply(d.root, ops)
+	if err != nil {
+		return err
+	}
+	d.root = root
+	return nil
+}
+
+func (d *Document) entries() []interface{} {
+	if m, ok := d.root.(map[string]interface{}); ok {
+		if entries, ok := m["entries"].([]interface{}); ok {
+			return entries
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries.
+func (d *Document) Len() int {
+	return len(d.entries())
+}
+
+// Entry decodes the entry at index i.
+func (d *Document) Entry(i int) (Entry, error) {
+	entries := d.entries()
+	if i < 0 || i >= len(entries) {
+		return Entry{}, fmt.Errorf("entry %d out of range", i)
+	}
+	raw, err := json.Marshal(entries[i])
+	if err != nil {
+		return Entry{}, err
+	}
+	return decodeEntry(raw)
+}
+
+// Entries decodes every entry in order.
+func (d *Document) Entries() ([]Entry, error) {
+	result := make([]Entry, d.Len())
+	for i := range result {
+		entry, err := d.Entry(i)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		result[i] = entry
+	}
+	return result, nil
+}
+
+// decodeEntry reads a {"type": kind, "content": ...} patch value.
+func decodeEntry(raw []byte) (Entry, error) {
+	var wrapper struct {
+		Type    string          `json:"type"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Kind: wrapper.Type}
+	if wrapper.Type != KindNormalized {
+		var text string
+		if err := json.Unmarshal(wrapper.Content, &text); err == nil {
+			entry.Content = text
+		} else {
+			entry.Content = string(wrapper.Content)
+		}
+		return entry, nil
+	}
+
+	var normalized struct {
+		Timestamp *string         `json:"timestamp"`
+		EntryType EntryType       `json:"entry_type"`
+		Content   string          `json:"content"`
+		Metadata  json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(wrapper.Content, &normalized); err != nil {
+		return Entry{}, err
+	}
+	entry.EntryType = normalized.EntryType
+	entry.Content = normalized.Content
+	if len(normalized.Metadata) > 0 && string(normalized.Metadata) != "null" {
+		entry.Metadata = normalized.Metadata
+	}
+	if normalized.Timestamp != nil {
+		if ts, err := time.Parse(time.RFC3339Nano, *normalized.Timestamp); err == nil {
+			entry.Timestamp = &ts
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseCSV reads a CSV file whose header row names a title column and
+// optionally description and status columns (case-insensitive).
+func ParseCSV(r io.Reader) ([]Item, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	titleCol, ok := columns["title"]
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain a title column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var items []Item
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if titleCol >= len(record) || strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		items = append(items, Item{
+			Title:       record[titleCol],
+			Description: field(record, "description"),
+			Status:      field(record, "status"),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,62 @@
+// Package importer reads task lists from Markdown checklists, YAML and CSV.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Item is a task to be created.
+type Item struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Status is the raw status from the file, empty when unspecified.
+	Status string `json:"status,omitempty"`
+}
+
+// Formats lists the accepted input formats.
+var Formats = []string{"md", "yaml", "csv"}
+
+// DetectFormat guesses the format from a file extension.
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return "md", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	case ".csv":
+		return "csv", nil
+	}
+	return "", fmt.Errorf("cannot detect the format of %q; pass --from %s", path, strings.Join(Formats, "|"))
+}
+
+// Parse reads items in the given format.
+func Parse(r io.Reader, format string) ([]Item, error) {
+	var items []Item
+	var err error
+	switch format {
+	case "md", "markdown":
+		items, err = ParseMarkdown(r)
+	case "yaml", "yml":
+		items, err = ParseYAML(r)
+	case "csv":
+		items, err = ParseCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		items[i].Title = strings.TrimSpace(item.Title)
+		items[i].Description = strings.TrimSpace(item.Description)
+		items[i].Status = strings.TrimSpace(item.Status)
+		if items[i].Title == "" {
+			return nil, fmt.Errorf("item %d has no title", i+1)
+		}
+	}
+	return items, nil
+}
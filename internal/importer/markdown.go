@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	checklistRe = regexp.MustCompile(`^([ \t]*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+)
+
+// ParseMarkdown reads tasks from a Markdown document:
+//
+//   - every checklist item ("- [ ] title") is a task; lines indented below it
+//     form its description and "- [x]" marks it done;
+//   - a heading without checklist items is a task whose body text is its
+//     description, unless it only groups deeper headings.
+func ParseMarkdown(r io.Reader) ([]Item, error) {
+	type section struct {
+		level    int
+		title    string
+		body     []string
+		hasItems bool
+		// at is the number of checklist items that precede the heading.
+		at int
+	}
+
+	var (
+		items    []Item
+		sections []*section
+		current  *section
+		item     *Item
+		itemBody []string
+		itemPad  int
+	)
+
+	flushItem := func() {
+		if item == nil {
+			return
+		}
+		item.Description = dedent(itemBody)
+		items = append(items, *item)
+		item, itemBody = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flushItem()
+			current = &section{level: len(m[1]), title: m[2], at: len(items)}
+			sections = append(sections, current)
+			continue
+		}
+
+		if m := checklistRe.FindStringSubmatch(line); m != nil {
+			pad := indentWidth(m[1])
+			if item == nil || pad <= itemPad {
+				flushItem()
+				item = &Item{Title: m[3]}
+				if m[2] != " " {
+					item.Status = "done"
+				}
+				itemPad = pad
+				if current != nil {
+					current.hasItems = true
+				}
+				continue
+			}
+		}
+
+		if item != nil {
+			if line == "" || indentWidth(leadingSpace(line)) > itemPad {
+				itemBody = append(itemBody, line)
+				continue
+			}
+			flushItem()
+		}
+		if current != nil {
+			current.body = append(current.body, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flushItem()
+
+	// Merge heading tasks back between the checklist items in document order.
+	var result []Item
+	next := 0
+	for i, s := range sections {
+		if s.hasItems {
+			continue
+		}
+		body := strings.TrimSpace(strings.Join(s.body, "\n"))
+		groupsSubsections := i+1 < len(sections) && sections[i+1].level > s.level
+		if body == "" && groupsSubsections {
+			continue
+		}
+		result = append(result, items[next:s.at]...)
+		next = s.at
+		result = append(result, Item{Title: s.title, Description: body})
+	}
+	return append(result, items[next:]...), nil
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// dedent removes the common indentation of lines and trims blank edges.
+func dedent(lines []string) string {
+	min := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if w := len(leadingSpace(l)); min < 0 || w < min {
+			min = w
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= min && min > 0 {
+			out[i] = l[min:]
+		} else {
+			out[i] = strings.TrimLeft(l, " \t")
+		}
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseYAML reads a YAML sequence of tasks, optionally nested under a
+// top-level "tasks:" key. Each element is either a plain title or a mapping
+// with title, description and status keys; an element whose first key is
+// anything else, such as "- fix: login bug", is a title. Only the subset of
+// YAML needed for such files is supported: plain, quoted and block (| and >)
+// scalars.
+func ParseYAML(r io.Reader) ([]Item, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p := &yamlParser{lines: lines}
+	p.skipBlank()
+	if p.done() {
+		return nil, nil
+	}
+
+	if line := p.current(); !isSeqEntry(strings.TrimSpace(line)) {
+		key, rest, ok := splitKey(strings.TrimSpace(line))
+		if !ok || key != "tasks" || rest != "" {
+			return nil, p.errorf("expected a list of tasks or a tasks: key")
+		}
+		p.pos++
+		p.skipBlank()
+	}
+
+	var items []Item
+	seqIndent := -1
+	for p.skipBlank(); !p.done(); p.skipBlank() {
+		line := p.current()
+		indent := indentOf(line)
+		content := strings.TrimSpace(line)
+		if !isSeqEntry(content) {
+			return nil, p.errorf("expected a list entry starting with -")
+		}
+		if seqIndent < 0 {
+			seqIndent = indent
+		} else if indent != seqIndent {
+			return nil, p.errorf("inconsistent indentation")
+		}
+
+		entry := strings.TrimSpace(strings.TrimPrefix(content, "-"))
+		p.pos++
+
+		if key, _, ok := splitKey(entry); !ok || !isTaskKey(key) {
+			title, err := p.scalar(entry, indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, Item{Title: title})
+			continue
+		}
+
+		// The first key shares the line with "-"; its column defines the
+		// indentation of the remaining keys.
+		keyIndent := indent + (len(content) - len(strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")))
+		fields := map[string]string{}
+		if err := p.field(entry, keyIndent, fields); err != nil {
+			return nil, err
+		}
+		for p.skipBlank(); !p.done(); p.skipBlank() {
+			line := p.current()
+			if indentOf(line) <= seqIndent {
+				break
+			}
+			if indentOf(line) != keyIndent {
+				return nil, p.errorf("unexpected indentation")
+			}
+			p.pos++
+			if err := p.field(strings.TrimSpace(line), keyIndent, fields); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, Item{
+			Title:       fields["title"],
+			Description: fields["description"],
+			Status:      fields["status"],
+		})
+	}
+	return items, nil
+}
+
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) done() bool {
+	return p.pos >= len(p.lines)
+}
+
+func (p *yamlParser) current() string {
+	return p.lines[p.pos]
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("yaml line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// skipBlank advances past empty lines, comments and document markers.
+func (p *yamlParser) skipBlank() {
+	for !p.done() {
+		trimmed := strings.TrimSpace(p.current())
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && trimmed != "---" {
+			return
+		}
+		p.pos++
+	}
+}
+
+// field parses "key: value" (the line has already been consumed) into fields.
+func (p *yamlParser) field(content string, indent int, fields map[string]string) error {
+	key, rest, ok := splitKey(content)
+	if !ok {
+		p.pos--
+		return p.errorf("expected key: value")
+	}
+	value, err := p.scalar(rest, indent)
+	if err != nil {
+		return err
+	}
+	fields[strings.ToLower(key)] = value
+	return nil
+}
+
+// scalar decodes an inline value, reading the following lines for block
+// scalars that are indented deeper than parentIndent.
+func (p *yamlParser) scalar(raw string, parentIndent int) (string, error) {
+	raw = stripYAMLComment(raw)
+	switch {
+	case strings.HasPrefix(raw, "|") || strings.HasPrefix(raw, ">"):
+		return p.block(raw, parentIndent), nil
+	case strings.HasPrefix(raw, "\""):
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", p.errorf("invalid double-quoted string")
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", p.errorf("invalid single-quoted string")
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	case raw == "~" || raw == "null":
+		return "", nil
+	}
+	return raw, nil
+}
+
+func (p *yamlParser) block(header string, parentIndent int) string {
+	folded := strings.HasPrefix(header, ">")
+	chomp := strings.TrimLeft(header, "|>")
+
+	var body []string
+	blockIndent := -1
+	for !p.done() {
+		line := p.current()
+		if strings.TrimSpace(line) == "" {
+			body = append(body, "")
+			p.pos++
+			continue
+		}
+		indent := indentOf(line)
+		if indent <= parentIndent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = indent
+		}
+		if indent < blockIndent {
+			break
+		}
+		body = append(body, line[blockIndent:])
+		p.pos++
+	}
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+
+	var text string
+	if folded {
+		var b strings.Builder
+		for i, l := range body {
+			switch {
+			case l == "":
+				b.WriteString("\n")
+				continue
+			case i == 0 || body[i-1] == "":
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(body, "\n")
+	}
+	if !strings.HasPrefix(chomp, "-") && text != "" {
+		text += "\n"
+	}
+	return text
+}
+
+func isSeqEntry(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// isTaskKey reports whether key is one of the keys of a task mapping.
+func isTaskKey(key string) bool {
+	switch strings.ToLower(key) {
+	case "title", "description", "status":
+		return true
+	}
+	return false
+}
+
+// splitKey splits "key: value" or "key:"; quoted strings are not keys.
+func splitKey(content string) (key, rest string, ok bool) {
+	if strings.HasPrefix(content, "\"") || strings.HasPrefix(content, "'") {
+		return "", "", false
+	}
+	idx := strings.Index(content, ": ")
+	if idx < 0 {
+		if strings.HasSuffix(content, ":") {
+			return strings.TrimSuffix(content, ":"), "", true
+		}
+		return "", "", false
+	}
+	key = content[:idx]
+	if strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(content[idx+2:]), true
+}
+
+func stripYAMLComment(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "\"") || strings.HasPrefix(raw, "'") {
+		return raw
+	}
+	if idx := strings.Index(raw, " #"); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	if strings.HasPrefix(raw, "#") {
+		return ""
+	}
+	return raw
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,209 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func mustDecode(t *testing.T, raw string) interface{} {
+	t.Helper()
+	v, err := Decode([]byte(raw))
+	if err != nil {
+		t.Fatalf("Decode(%s): %v", raw, err)
+	}
+	return v
+}
+
+func mustOps(t *testing.T, raw string) []Operation {
+	t.Helper()
+	var ops []Operation
+	if err := json.Unmarshal([]byte(raw), &ops); err != nil {
+		t.Fatalf("invalid patch %s: %v", raw, err)
+	}
+	return ops
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add member",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"/b","value":2}]`,
+			want:  `{"a":1,"b":2}`,
+		},
+		{
+			name:  "add replaces existing member",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"/a","value":[1]}]`,
+			want:  `{"a":[1]}`,
+		},
+		{
+			name:  "add inserts into array",
+			doc:   `{"entries":["a","c"]}`,
+			patch: `[{"op":"add","path":"/entries/1","value":"b"}]`,
+			want:  `{"entries":["a","b","c"]}`,
+		},
+		{
+			name:  "add at array length appends",
+			doc:   `{"entries":["a"]}`,
+			patch: `[{"op":"add","path":"/entries/1","value":"b"}]`,
+			want:  `{"entries":["a","b"]}`,
+		},
+		{
+			name:  "add with dash appends",
+			doc:   `{"entries":["a"]}`,
+			patch: `[{"op":"add","path":"/entries/-","value":"b"},{"op":"add","path":"/entries/-","value":"c"}]`,
+			want:  `{"entries":["a","b","c"]}`,
+		},
+		{
+			name:  "add into nested array",
+			doc:   `{"x":{"list":[]}}`,
+			patch: `[{"op":"add","path":"/x/list/0","value":{"k":true}}]`,
+			want:  `{"x":{"list":[{"k":true}]}}`,
+		},
+		{
+			name:  "add replaces root",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"","value":{"entries":[]}}]`,
+			want:  `{"entries":[]}`,
+		},
+		{
+			name:  "remove member",
+			doc:   `{"a":1,"b":2}`,
+			patch: `[{"op":"remove","path":"/a"}]`,
+			want:  `{"b":2}`,
+		},
+		{
+			name:  "remove array element",
+			doc:   `{"entries":["a","b","c"]}`,
+			patch: `[{"op":"remove","path":"/entries/1"}]`,
+			want:  `{"entries":["a","c"]}`,
+		},
+		{
+			name:  "replace array element",
+			doc:   `{"entries":["a","b"]}`,
+			patch: `[{"op":"replace","path":"/entries/1","value":"B"}]`,
+			want:  `{"entries":["a","B"]}`,
+		},
+		{
+			name:  "replace member",
+			doc:   `{"a":{"b":1}}`,
+			patch: `[{"op":"replace","path":"/a/b","value":"x"}]`,
+			want:  `{"a":{"b":"x"}}`,
+		},
+		{
+			name:  "move member",
+			doc:   `{"a":{"b":1},"c":{}}`,
+			patch: `[{"op":"move","from":"/a/b","path":"/c/d"}]`,
+			want:  `{"a":{},"c":{"d":1}}`,
+		},
+		{
+			name:  "move within array",
+			doc:   `{"entries":["a","b","c"]}`,
+			patch: `[{"op":"move","from":"/entries/0","path":"/entries/2"}]`,
+			want:  `{"entries":["b","c","a"]}`,
+		},
+		{
+			name:  "copy is deep",
+			doc:   `{"a":{"b":[1]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`,
+			want:  `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:  "test passes",
+			doc:   `{"a":{"b":[1,"x"]}}`,
+			patch: `[{"op":"test","path":"/a","value":{"b":[1,"x"]}},{"op":"add","path":"/ok","value":true}]`,
+			want:  `{"a":{"b":[1,"x"]},"ok":true}`,
+		},
+		{
+			name:  "escaped pointer tokens",
+			doc:   `{"entries":{}}`,
+			patch: `[{"op":"add","path":"/entries/src~1api.rs","value":1},{"op":"add","path":"/entries/a~0b","value":2}]`,
+			want:  `{"entries":{"src/api.rs":1,"a~b":2}}`,
+		},
+		{
+			name:  "escape order is ~1 before ~0",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/~01","value":1}]`,
+			want:  `{"~1":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(mustDecode(t, tt.doc), mustOps(t, tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if want := mustDecode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+	}{
+		{"test fails", `{"a":1}`, `[{"op":"test","path":"/a","value":2}]`},
+		{"remove missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`},
+		{"replace missing member", `{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`},
+		{"dash outside add", `{"a":[1]}`, `[{"op":"remove","path":"/a/-"}]`},
+		{"index out of range", `{"a":[1]}`, `[{"op":"add","path":"/a/2","value":1}]`},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`},
+		{"pointer without slash", `{}`, `[{"op":"add","path":"a","value":1}]`},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`},
+		{"move into own child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`},
+		{"unknown op", `{}`, `[{"op":"merge","path":"/a","value":1}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(mustDecode(t, tt.doc), mustOps(t, tt.patch)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	doc := mustDecode(t, `{"entries":["a","b"],"meta":{"n":1}}`)
+	before := mustDecode(t, `{"entries":["a","b"],"meta":{"n":1}}`)
+
+	ops := mustOps(t, `[
+		{"op":"add","path":"/entries/-","value":"c"},
+		{"op":"replace","path":"/meta/n","value":2},
+		{"op":"remove","path":"/entries/0"},
+		{"op":"remove","path":"/missing"}
+	]`)
+	got, err := Apply(doc, ops)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got != nil {
+		t.Errorf("got document %v on failure, want nil", got)
+	}
+	if !reflect.DeepEqual(doc, before) {
+		t.Errorf("document changed by a failed patch: %v", doc)
+	}
+}
+
+func TestDecodeKeepsNumbers(t *testing.T) {
+	got, err := Apply(mustDecode(t, `{}`), mustOps(t, `[{"op":"add","path":"/n","value":12345678901234567890}]`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if n := got.(map[string]interface{})["n"]; n != json.Number("12345678901234567890") {
+		t.Errorf("got %#v, want the exact json.Number", n)
+	}
+}
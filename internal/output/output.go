@@ -0,0 +1,155 @@
+// Package output renders command results in machine-readable formats.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// Mode selects how a command prints its result.
+type Mode string
+
+const (
+	Table    Mode = "table"
+	JSON     Mode = "json"
+	YAML     Mode = "yaml"
+	TSV      Mode = "tsv"
+	Template Mode = "template"
+)
+
+// Modes lists the accepted --output values.
+var Modes = []Mode{Table, JSON, YAML, TSV, Template}
+
+// Format is a parsed --output / --format pair.
+type Format struct {
+	Mode     Mode
+	Template string
+}
+
+// Parse validates an --output value and an optional --format template.
+// A template implies the template mode.
+func Parse(mode, tmpl string) (Format, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if tmpl != "" {
+		if mode != "" && Mode(mode) != Template {
+			return Format{}, fmt.Errorf("--format cannot be combined with --output %s", mode)
+		}
+		if _, err := newTemplate(tmpl); err != nil {
+			return Format{}, fmt.Errorf("invalid --format template: %w", err)
+		}
+		return Format{Mode: Template, Template: tmpl}, nil
+	}
+	if mode == "" {
+		return Format{Mode: Table}, nil
+	}
+	for _, m := range Modes {
+		if Mode(mode) == m {
+			if m == Template {
+				return Format{}, fmt.Errorf("--output template requires --format")
+			}
+			return Format{Mode: m}, nil
+		}
+	}
+	return Format{}, fmt.Errorf("unknown output %q (expected table, json, yaml or tsv)", mode)
+}
+
+// IsTable reports whether the human-readable table should be printed.
+func (f Format) IsTable() bool {
+	return f.Mode == "" || f.Mode == Table
+}
+
+// Columns describes the TSV representation of a value.
+type Columns struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// Write renders v in the given machine-readable format. cols is only used
+// for TSV. Slices rendered with a template produce one line per element.
+func Write(w io.Writer, f Format, v interface{}, cols Columns) error {
+	switch f.Mode {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case YAML:
+		return EncodeYAML(w, v)
+	case TSV:
+		return writeTSV(w, cols)
+	case Template:
+		return writeTemplate(w, f.Template, v)
+	}
+	return fmt.Errorf("output mode %q has no machine-readable form", f.Mode)
+}
+
+func writeTSV(w io.Writer, cols Columns) error {
+	if _, err := fmt.Fprintln(w, strings.Join(cols.Headers, "\t")); err != nil {
+		return err
+	}
+	for _, row := range cols.Rows {
+		fields := make([]string, len(row))
+		for i, field := range row {
+			fields[i] = escapeTSV(field)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+func escapeTSV(s string) string {
+	return tsvEscaper.Replace(s)
+}
+
+func newTemplate(text string) (*template.Template, error) {
+	return template.New("format").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			raw, err := json.Marshal(v)
+			return string(raw), err
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}).Parse(text)
+}
+
+func writeTemplate(w io.Writer, text string, v interface{}) error {
+	tmpl, err := newTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	items := []interface{}{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, item); err != nil {
+			return err
+		}
+		if buf.Len() == 0 {
+			continue
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
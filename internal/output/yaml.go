@@ -0,0 +1,220 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// node is an order-preserving view of a JSON document.
+type node struct {
+	kind   byte // 'o' object, 'a' array, 's' string, 'n' number, 'b' bool, 'z' null
+	keys   []string
+	values []*node
+	scalar string
+}
+
+// EncodeYAML writes v as YAML. v is first marshalled to JSON so that json
+// struct tags and field order are honoured.
+func EncodeYAML(w io.Writer, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	root, err := readNode(dec)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	switch {
+	case root.kind == 'o' && len(root.keys) > 0:
+		writeObject(&b, root, 0)
+	case root.kind == 'a' && len(root.values) > 0:
+		writeArray(&b, root, 0)
+	default:
+		b.WriteString(inlineValue(root, 0))
+		b.WriteString("\n")
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func readNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &node{kind: 'o'}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, keyTok.(string))
+				n.values = append(n.values, child)
+			}
+			_, err := dec.Token()
+			return n, err
+		case '[':
+			n := &node{kind: 'a'}
+			for dec.More() {
+				child, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.values = append(n.values, child)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+	case string:
+		return &node{kind: 's', scalar: t}, nil
+	case json.Number:
+		return &node{kind: 'n', scalar: t.String()}, nil
+	case bool:
+		return &node{kind: 'b', scalar: fmt.Sprint(t)}, nil
+	case nil:
+		return &node{kind: 'z', scalar: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// isBlock reports whether n is rendered on its own lines below its key.
+func isBlock(n *node) bool {
+	return (n.kind == 'o' && len(n.keys) > 0) || (n.kind == 'a' && len(n.values) > 0)
+}
+
+func writeObject(b *strings.Builder, n *node, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, key := range n.keys {
+		if i > 0 {
+			b.WriteString(pad)
+		}
+		writeEntry(b, quoteString(key)+":", n.values[i], indent)
+	}
+}
+
+func writeArray(b *strings.Builder, n *node, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, item := range n.values {
+		if i > 0 {
+			b.WriteString(pad)
+		}
+		if item.kind == 'o' && len(item.keys) > 0 {
+			b.WriteString("- ")
+			writeObject(b, item, indent+2)
+			continue
+		}
+		writeEntry(b, "-", item, indent)
+	}
+}
+
+// writeEntry writes "prefix value" where prefix is "key:" or "-". The caller
+// has already written the indentation for the first line.
+func writeEntry(b *strings.Builder, prefix string, value *node, indent int) {
+	b.WriteString(prefix)
+	if isBlock(value) {
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat(" ", indent+2))
+		if value.kind == 'o' {
+			writeObject(b, value, indent+2)
+		} else {
+			writeArray(b, value, indent+2)
+		}
+		return
+	}
+	b.WriteString(" ")
+	b.WriteString(inlineValue(value, indent+2))
+	b.WriteString("\n")
+}
+
+func inlineValue(n *node, indent int) string {
+	switch n.kind {
+	case 'o':
+		return "{}"
+	case 'a':
+		return "[]"
+	case 's':
+		if strings.Contains(n.scalar, "\n") && canUseLiteral(n.scalar) {
+			return literalBlock(n.scalar, indent)
+		}
+		return quoteString(n.scalar)
+	}
+	return n.scalar
+}
+
+// canUseLiteral reports whether s survives a YAML literal block unchanged.
+func canUseLiteral(s string) bool {
+	if strings.ContainsAny(s, "\r\t") || strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+func literalBlock(s string, indent int) string {
+	header := "|-"
+	if strings.HasSuffix(s, "\n") {
+		header = "|"
+		s = strings.TrimSuffix(s, "\n")
+	}
+	pad := strings.Repeat(" ", indent)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"null": true, "~": true, "y": true, "n": true,
+}
+
+func quoteString(s string) string {
+	if needsQuotes(s) {
+		raw, _ := json.Marshal(s)
+		return string(raw)
+	}
+	return s
+}
+
+func needsQuotes(s string) bool {
+	if s == "" || yamlReserved[strings.ToLower(s)] {
+		return true
+	}
+	if strings.TrimSpace(s) != s {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`0123456789.+") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SessionLog appends records of one interactive session, one JSON object
+// per line, to a file under Dir. The file is created with the first record.
+type SessionLog struct {
+	path string
+}
+
+// NewSessionLog returns the log of a session of kind (e.g. "reviews")
+// started at started: Dir/<kind>/<YYYYMMDD-HHMMSS>.jsonl.
+func NewSessionLog(kind string, started time.Time) *SessionLog {
+	name := started.Format("20060102-150405") + ".jsonl"
+	return &SessionLog{path: filepath.Join(Dir(), kind, name)}
+}
+
+// Path returns the file the log is written to.
+func (l *SessionLog) Path() string {
+	return l.path
+}
+
+// Append writes record as one line.
+func (l *SessionLog) Append(record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
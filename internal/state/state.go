@@ -0,0 +1,120 @@
+// Package state keeps local vkcli data that has no place on the server,
+// such as dependencies between tasks.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// State is the content of the state file.
+type State struct {
+	// Dependencies maps a task ID to the IDs of the tasks it depends on.
+	Dependencies map[string][]string `json:"dependencies,omitempty"`
+
+	path string
+}
+
+// Dir returns $XDG_STATE_HOME/vkcli, falling back to ~/.local/state/vkcli.
+func Dir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(".local", "state", "vkcli")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "vkcli")
+}
+
+// DefaultPath returns the state file, state.json in Dir.
+func DefaultPath() string {
+	return filepath.Join(Dir(), "state.json")
+}
+
+// Load reads the state file at path. A missing file yields an empty state.
+func Load(path string) (*State, error) {
+	s := &State{Dependencies: map[string][]string{}, path: path}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Dependencies == nil {
+		s.Dependencies = map[string][]string{}
+	}
+	return s, nil
+}
+
+// Path returns the file the state was loaded from.
+func (s *State) Path() string {
+	return s.path
+}
+
+// Save writes the state back to its file, replacing it atomically.
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// AddDependencies records that taskID depends on deps.
+func (s *State) AddDependencies(taskID string, deps ...string) {
+	current := s.Dependencies[taskID]
+	for _, dep := range deps {
+		if !slices.Contains(current, dep) {
+			current = append(current, dep)
+		}
+	}
+	sort.Strings(current)
+	s.Dependencies[taskID] = current
+}
+
+// RemoveDependencies forgets that taskID depends on deps and reports which
+// of them were recorded.
+func (s *State) RemoveDependencies(taskID string, deps ...string) []string {
+	var removed []string
+	var current []string
+	for _, dep := range s.Dependencies[taskID] {
+		if slices.Contains(deps, dep) {
+			removed = append(removed, dep)
+			continue
+		}
+		current = append(current, dep)
+	}
+	if len(current) == 0 {
+		delete(s.Dependencies, taskID)
+	} else {
+		s.Dependencies[taskID] = current
+	}
+	return removed
+}
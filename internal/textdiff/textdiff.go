@@ -0,0 +1,244 @@
+// Package textdiff produces unified diffs of two texts, line by line, using
+// Myers' O(ND) algorithm.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is how many unchanged lines surround each hunk, as in git.
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// edit is one line of the edit script: a line of a (delete), of b (insert)
+// or of both (equal).
+type edit struct {
+	Kind opKind
+	A, B int
+}
+
+// Unified returns the unified diff that turns oldText into newText, with
+// "--- oldName" and "+++ newName" headers, or "" when they are equal.
+// Counts of added and deleted lines are returned as well.
+func Unified(oldName, newName, oldText, newText string) (diff string, added, deleted int) {
+	a, b := splitLines(oldText), splitLines(newText)
+	script := diffLines(a, b)
+	for _, e := range script {
+		switch e.Kind {
+		case opInsert:
+			added++
+		case opDelete:
+			deleted++
+		}
+	}
+	if added == 0 && deleted == 0 {
+		return "", 0, 0
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(script) {
+		writeHunk(&out, script[h[0]:h[1]], a, b)
+	}
+	return out.String(), added, deleted
+}
+
+// splitLines splits text into lines, keeping a missing final newline as a
+// marker so that "a" and "a\n" differ.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maxEditDistance bounds the work of diffLines; texts that differ in more
+// lines than this are shown as replaced entirely.
+const maxEditDistance = 2000
+
+// diffLines returns the shortest edit script from a to b.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	script := make([]edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		script = append(script, edit{opEqual, i, i})
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		script = append(script, edit{e.Kind, e.A + prefix, e.B + prefix})
+	}
+	for i := suffix; i > 0; i-- {
+		script = append(script, edit{opEqual, len(a) - i, len(b) - i})
+	}
+	return script
+}
+
+// myers finds the shortest edit script with Myers' greedy algorithm. For
+// every D it records the furthest reaching x of each diagonal k in [-D, D],
+// which backtrack replays from the end.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEditDistance)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		// Diagonals -d-1 .. d+1 of the previous round, indexed by k+d+1.
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m, d)
+			}
+		}
+	}
+	return replaceAll(n, m)
+}
+
+// backtrack walks the recorded diagonals from (n, m) back to the origin.
+func backtrack(trace [][]int, n, m, d int) []edit {
+	script := make([]edit, 0, n+m)
+	x, y := n, m
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[k-1+d+1] < v[k+1+d+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+d+1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			script = append(script, edit{opEqual, x, y})
+		}
+		if x == prevX {
+			y--
+			script = append(script, edit{opInsert, x, y})
+		} else {
+			x--
+			script = append(script, edit{opDelete, x, y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		script = append(script, edit{opEqual, x, y})
+	}
+
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+// replaceAll is the script that deletes all of a and inserts all of b.
+func replaceAll(n, m int) []edit {
+	script := make([]edit, 0, n+m)
+	for i := 0; i < n; i++ {
+		script = append(script, edit{opDelete, i, 0})
+	}
+	for j := 0; j < m; j++ {
+		script = append(script, edit{opInsert, n, j})
+	}
+	return script
+}
+
+// hunks returns the [start, end) ranges of script that form hunks: changes
+// with up to contextLines of unchanged lines around them, merged when their
+// context would overlap.
+func hunks(script []edit) [][2]int {
+	var ranges [][2]int
+	for i, e := range script {
+		if e.Kind == opEqual {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+contextLines+1, len(script))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+	return ranges
+}
+
+func writeHunk(out *strings.Builder, script []edit, a, b []string) {
+	oldStart, newStart := script[0].A, script[0].B
+	oldCount, newCount := 0, 0
+	for _, e := range script {
+		if e.Kind != opInsert {
+			oldCount++
+		}
+		if e.Kind != opDelete {
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, e := range script {
+		var prefix byte
+		var line string
+		switch e.Kind {
+		case opEqual:
+			prefix, line = ' ', a[e.A]
+		case opDelete:
+			prefix, line = '-', a[e.A]
+		case opInsert:
+			prefix, line = '+', b[e.B]
+		}
+		out.WriteByte(prefix)
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk range the way diff -u does: the start line is
+// 1-based, except for empty ranges, which name the line before them.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -1,21 +1,46 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"vkcli/internal/commands"
+	"vkcli/internal/config"
+	"vkcli/internal/output"
 )
 
 func main() {
 	registerCommands()
 
-	if len(os.Args) < 2 {
+	args, globals, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Println("Error:", err)
+		printUsage()
+		os.Exit(1)
+	}
+
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	cmdName := os.Args[1]
+	cfg, err := config.Load(globals.server)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	commands.SetConfig(cfg)
+
+	format, err := output.Parse(globals.output, globals.format)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	commands.SetDefaultOutput(format)
+
+	cmdName := args[0]
 	cmd, ok := commands.Lookup(cmdName)
 	if !ok {
 		fmt.Println("Unknown command:", cmdName)
@@ -23,10 +48,53 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := cmd.Run(os.Args[2:]); err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
+	if err := cmd.Run(args[1:]); err != nil {
+		var exitErr *commands.ExitError
+		if !errors.As(err, &exitErr) || exitErr.Err != nil {
+			fmt.Println("Error:", err)
+		}
+		os.Exit(commands.ExitCode(err))
+	}
+}
+
+type globalFlags struct {
+	server string
+	output string
+	format string
+}
+
+// parseGlobalFlags consumes the options that precede the subcommand name.
+func parseGlobalFlags(args []string) (rest []string, globals globalFlags, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var target *string
+		switch {
+		case arg == "--server":
+			target = &globals.server
+		case arg == "--output" || arg == "-o":
+			target = &globals.output
+		case arg == "--format":
+			target = &globals.format
+		case strings.HasPrefix(arg, "--server="):
+			globals.server = strings.TrimPrefix(arg, "--server=")
+		case strings.HasPrefix(arg, "--output="):
+			globals.output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "--format="):
+			globals.format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-"):
+			return nil, globals, fmt.Errorf("unknown global flag: %s", arg)
+		default:
+			return args[i:], globals, nil
+		}
+		if target != nil {
+			if i+1 >= len(args) {
+				return nil, globals, fmt.Errorf("%s requires a value", arg)
+			}
+			*target = args[i+1]
+			i++
+		}
 	}
+	return nil, globals, nil
 }
 
 func registerCommands() {
@@ -36,10 +104,27 @@ func registerCommands() {
 	commands.Register(commands.NewExecCommand())
 	commands.Register(commands.NewStatusCommand())
 	commands.Register(commands.NewPickCommand())
+	commands.Register(commands.NewTaskCommand())
+	commands.Register(commands.NewImportCommand())
+	commands.Register(commands.NewExportCommand())
+	commands.Register(commands.NewLogsCommand())
+	commands.Register(commands.NewStopCommand())
+	commands.Register(commands.NewFollowupCommand())
+	commands.Register(commands.NewRunQueueCommand())
+	commands.Register(commands.NewDepsCommand())
+	commands.Register(commands.NewAttemptsCommand())
+	commands.Register(commands.NewDiffCommand())
+	commands.Register(commands.NewMergeCommand())
+	commands.Register(commands.NewRebaseCommand())
+	commands.Register(commands.NewPRCommand())
+	commands.Register(commands.NewReviewCommand())
+	commands.Register(commands.NewConfigCommand())
 }
 
 func printUsage() {
 	fmt.Println("Usage:")
+	fmt.Println("  vkcli [--server <url>] [--output table|json|yaml|tsv] [--format <template>] <command> [args]")
+	fmt.Println()
 	for _, cmd := range commands.All() {
 		fmt.Printf("  %-36s # %s\n", cmd.Usage(), cmd.Description())
 	}